@@ -0,0 +1,184 @@
+// MIT License
+//
+// Copyright (c) 2022 pmadhav
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package gogm
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+//ChangeSet lists the changes made to a domain object since it was loaded or saved in the session. Every property,
+//label and relationship of a domain object that isn't managed by the session is a change
+type ChangeSet struct {
+	//Properties are the changed properties keyed by their name in the database
+	Properties           map[string]PropertyChange
+	AddedLabels          []string
+	RemovedLabels        []string
+	AddedRelationships   []RelationshipChange
+	RemovedRelationships []RelationshipChange
+}
+
+//PropertyChange is the value of a property when the domain object was loaded or saved, and its current value
+type PropertyChange struct {
+	Old interface{}
+	New interface{}
+}
+
+//RelationshipChange is a relationship added to or removed from a node. Relationship is the relationship entity,
+//nil when the relationship has no entity, and Node is the domain object at the other end of the relationship
+type RelationshipChange struct {
+	Type         string
+	Relationship interface{}
+	Node         interface{}
+}
+
+//IsEmpty returns true when nothing changed
+func (c *ChangeSet) IsEmpty() bool {
+	return len(c.Properties) == 0 && len(c.AddedLabels) == 0 && len(c.RemovedLabels) == 0 && len(c.AddedRelationships) == 0 && len(c.RemovedRelationships) == 0
+}
+
+//changes compares object with the state of the session store. It returns whether object is managed by the session
+//along with its changes. Like save, it considers the relationships of object but not the changes of related objects
+func (s *saver) changes(object interface{}) (bool, *ChangeSet, error) {
+	var (
+		graphs        []graph
+		metadata      metadata
+		relationships map[int64]graph
+		removed       map[int64]graph
+		otherNodes    map[int64]graph
+		err           error
+
+		ID        = getTemporaryIDer(s.store)
+		changeSet = &ChangeSet{Properties: map[string]PropertyChange{}}
+	)
+
+	if graphs, err = s.graphFactory.get(reflect.ValueOf(object), nil, emptyString); err != nil {
+		return false, nil, err
+	}
+	if len(graphs) != 1 || !graphs[0].getValue().IsValid() {
+		return false, nil, errors.New("Changes are computed for a single domain object. Expecting a pointer to a pointer of domain object")
+	}
+
+	g := graphs[0]
+	ID(g)
+	stored := s.store.get(g)
+
+	var storedProperties map[string]interface{}
+	if stored != nil {
+		storedProperties = stored.getProperties()
+	}
+	for name, property := range diffProperties(getWritableProperties(g.getProperties()), storedProperties) {
+		changeSet.Properties[name] = PropertyChange{storedProperties[name], property}
+	}
+
+	if reflect.TypeOf(g) != typeOfPrivateNode {
+		return stored != nil, changeSet, nil
+	}
+
+	var storedLabel string
+	if stored != nil {
+		storedLabel = stored.getLabel()
+	}
+	changeSet.AddedLabels, changeSet.RemovedLabels = diffLabels(g.getLabel(), storedLabel)
+
+	if metadata, err = s.registry.get(g.getValue().Type(), emptyString); err != nil {
+		return false, nil, err
+	}
+	if relationships, err = metadata.loadRelatedGraphs(g, ID, s.registry, emptyString); err != nil {
+		return false, nil, err
+	}
+	for _, relationship := range relationships {
+		g.setRelatedGraph(relationship)
+		if stored == nil || stored.getRelatedGraphs()[relationship.getID()] == nil {
+			otherNode := relationship.getRelatedGraphs()[startNode]
+			if otherNode.getID() == g.getID() {
+				otherNode = relationship.getRelatedGraphs()[endNode]
+			}
+			changeSet.AddedRelationships = append(changeSet.AddedRelationships, getRelationshipChange(relationship, otherNode))
+		}
+	}
+
+	if stored != nil {
+		var cBuilder *nodeQueryBuilder
+		if cBuilder, err = newNodeCypherBuilder(g.(*node), s.registry, stored, emptyString); err != nil {
+			return false, nil, err
+		}
+		removed, otherNodes = cBuilder.getRemovedGraphs()
+		for internalID, relationship := range removed {
+			changeSet.RemovedRelationships = append(changeSet.RemovedRelationships, getRelationshipChange(relationship, otherNodes[internalID]))
+		}
+	}
+
+	for _, relationshipChanges := range [][]RelationshipChange{changeSet.AddedRelationships, changeSet.RemovedRelationships} {
+		sort.SliceStable(relationshipChanges, func(i, j int) bool { return relationshipChanges[i].Type < relationshipChanges[j].Type })
+	}
+
+	return stored != nil, changeSet, nil
+}
+
+func getRelationshipChange(r graph, otherNode graph) RelationshipChange {
+	relationshipChange := RelationshipChange{Type: r.(*relationship).getType()}
+	if r.getValue() != nil && r.getValue().IsValid() {
+		relationshipChange.Relationship = r.getValue().Interface()
+	}
+	if otherNode != nil && otherNode.getValue() != nil && otherNode.getValue().IsValid() {
+		relationshipChange.Node = otherNode.getValue().Interface()
+	}
+	return relationshipChange
+}
+
+//diffLabels returns the labels of label that aren't labels of storedLabel and the labels of storedLabel that
+//aren't labels of label
+func diffLabels(label string, storedLabel string) ([]string, []string) {
+	var (
+		added   []string
+		removed []string
+		labels  = map[string]bool{}
+		stored  = map[string]bool{}
+	)
+	for _, l := range strings.Split(label, labelsDelim) {
+		if l != emptyString {
+			labels[l] = true
+		}
+	}
+	for _, l := range strings.Split(storedLabel, labelsDelim) {
+		if l != emptyString {
+			stored[l] = true
+		}
+	}
+	for l := range labels {
+		if !stored[l] {
+			added = append(added, l)
+		}
+	}
+	for l := range stored {
+		if !labels[l] {
+			removed = append(removed, l)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
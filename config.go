@@ -29,4 +29,19 @@ type Config struct {
 	Password       string
 	LogLevel       LogLevel
 	AllowCyclicRef bool
+
+	//SchemaDialect is the syntax of the statements creating constraints and indexes. By default, it's
+	//detected from the version of the server
+	SchemaDialect SchemaDialect
+
+	//Logger, when set, logs the statements run by the OGM at debug level, failed ones at error level. The logs of
+	//the driver are routed to it up to LogLevel instead of the console
+	Logger Logger
+
+	//QueryHook, when set, is called around every statement run by the OGM
+	QueryHook QueryHook
+
+	//RedactParameter, when set, returns the value of the parameter name, or of a property name of a map parameter,
+	//to report to Logger and QueryHook, e.g. to mask secrets
+	RedactParameter func(name string, value interface{}) interface{}
 }
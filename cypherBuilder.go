@@ -22,19 +22,20 @@
 
 package gogm
 
-import (
-	"reflect"
-	"strings"
-)
-
 type graphQueryBuilder interface {
 	getCreate() (string, string, map[string]interface{}, map[string]graph)
+	getMerge(dbName string) (string, string, map[string]interface{}, map[string]graph, error)
 	getMatch(dbName string) (string, map[string]interface{}, map[string]graph)
 	getSet() (string, map[string]interface{})
+	getLock(dbName string) (string, string, map[string]interface{}, error)
+	getUnwindCreate(merge bool, dbName string) (string, map[string]interface{}, error)
+	getUnwindSet(dbName string) (string, map[string]interface{}, error)
+	getUnwindDelete() (string, map[string]interface{})
 	getDelete(dbName string) (string, map[string]interface{}, map[string]graph)
-	getLoadAll(IDs interface{}, lo *LoadOptions) (string, map[string]interface{})
+	getLoadAll(IDs interface{}, lo *LoadOptions) (string, map[string]interface{}, error)
+	getSoftDelete(softDeleteName string, softDeleted interface{}) (string, map[string]interface{})
 	getDeleteAll() (string, map[string]interface{})
-	getCountEntitiesOfType() (string, map[string]interface{})
+	getCountEntitiesOfType(lo *LoadOptions) (string, map[string]interface{}, error)
 
 	getGraph() graph
 	isGraphDirty() bool
@@ -61,48 +62,13 @@ func newCypherBuilder(g graph, registry *registry, store store, dbName string) (
 	return qGraphBuilder, nil
 }
 
-func getCreateSchemaStatement(metadata metadata) []string {
-
-	var indexes []string
-	var unique = map[string]bool{}
-	var statements []string
-	var objectMetadata *nodeMetadata
-	var ok bool
-
-	if typeOfNodeMetadata != reflect.TypeOf(metadata) {
-		return statements
-	}
-
-	if objectMetadata, ok = metadata.(*nodeMetadata); !ok {
-		return nil
-	}
-
-	for name, structField := range metadata.getPropertyStructFields() {
-		namespaceTag := getNamespacedTag(structField.Tag)
-		if (len(namespaceTag.get(uniqueTag)) > 0 || len(namespaceTag.get(customIDTag)) > 0) && !unique[name] {
-			unique[name] = true
-		}
-	}
-
-	for name, structField := range metadata.getPropertyStructFields() {
-		namespaceTag := getNamespacedTag(structField.Tag)
-		if len(namespaceTag.get(indexTag)) > 0 && !unique[name] {
-			indexes = append(indexes, name)
-		}
-	}
-
-	for name := range unique {
-		for _, label := range objectMetadata.thisStructLabel {
-			statements = append(statements, `CREATE CONSTRAINT IF NOT EXISTS ON (a:`+label+`) ASSERT a.`+name+` IS UNIQUE`)
-		}
-	}
-
-	compositeIndexes := strings.Join(indexes, indexDelim)
-	if compositeIndexes != emptyString {
-		for _, label := range objectMetadata.thisStructLabel {
-			statements = append(statements, `CREATE INDEX ON :`+label+`(`+compositeIndexes+`)`)
-
+//getWritableProperties returns properties without the meta properties, which can't be written
+func getWritableProperties(properties map[string]interface{}) map[string]interface{} {
+	writableProperties := map[string]interface{}{}
+	for propertyName, propertyValue := range properties {
+		if !metaProperties[propertyName] {
+			writableProperties[propertyName] = propertyValue
 		}
 	}
-	return statements
+	return writableProperties
 }
@@ -23,98 +23,192 @@
 package gogm
 
 import (
+	"context"
+	"time"
+
 	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
 	"github.com/neo4j/neo4j-go-driver/v4/neo4j/db"
 )
 
 type transactionExecuter func(work neo4j.TransactionWork, configurers ...func(*neo4j.TransactionConfig)) (interface{}, error)
 
+//statementRunner runs a statement in the transaction of a unit of work and collects its records
+type statementRunner func(cql string, params map[string]interface{}) ([]*neo4j.Record, error)
+
 type cypherExecuter struct {
 	driver      neo4j.Driver
 	accessMode  neo4j.AccessMode
 	transaction *transaction
+	tracer      *tracer
+
+	//registry creates the postponed schemas of a database before statements run in it. It's nil for the
+	//executer of the registry
+	registry *registry
 }
 
-func newCypherExecuter(driver neo4j.Driver, accessMode neo4j.AccessMode, t *transaction) *cypherExecuter {
-	return &cypherExecuter{driver, accessMode, nil}
+func newCypherExecuter(driver neo4j.Driver, accessMode neo4j.AccessMode, tracer *tracer) *cypherExecuter {
+	return &cypherExecuter{driver, accessMode, nil, tracer, nil}
 }
 
-func (c *cypherExecuter) execTransaction(te transactionExecuter, cql string, params map[string]interface{}) (neo4j.Result, error) {
+func (c *cypherExecuter) execTransaction(ctx context.Context, te transactionExecuter, cql string, params map[string]interface{}, configurers []func(*neo4j.TransactionConfig)) (neo4j.Result, error) {
 	var (
 		err    error
 		result neo4j.Result
 	)
 
 	if _, err = te(func(tx neo4j.Transaction) (interface{}, error) {
+		if err = ctx.Err(); err != nil {
+			return nil, err
+		}
 		if result, err = tx.Run(cql, params); err != nil {
 			return nil, err
 		}
 		return result, nil
-	}); err != nil {
+	}, configurers...); err != nil {
 		return nil, err
 	}
 
 	return result, nil
 }
 
-func (c *cypherExecuter) execTransactionCollect(te transactionExecuter, cql string, params map[string]interface{}) (interface{}, error) {
+func (c *cypherExecuter) execTransactionCollect(ctx context.Context, te transactionExecuter, cql string, params map[string]interface{}, configurers []func(*neo4j.TransactionConfig)) (interface{}, error) {
 	var (
 		err     error
 		result  neo4j.Result
 		records interface{}
+		stats   *WriteStats
+		profile *Profile
 	)
 
 	if records, err = te(func(tx neo4j.Transaction) (interface{}, error) {
+		stats, profile = newAttemptStats(ctx), newAttemptProfile(ctx)
+		if err = ctx.Err(); err != nil {
+			return nil, err
+		}
 		if result, err = tx.Run(cql, params); err != nil {
 			return nil, err
 		}
-		return result.Collect()
-	}); err != nil {
+		records, err := collectWithContext(ctx, result)
+		if err != nil {
+			return nil, err
+		}
+		return records, consume(result, stats, profile)
+	}, configurers...); err != nil {
 		return nil, err
 	}
 
+	getWriteStats(ctx).merge(stats)
+	getProfile(ctx).merge(profile)
 	return records, nil
 }
 
-func (c *cypherExecuter) execTransactionSingle(te transactionExecuter, cql string, params map[string]interface{}) (interface{}, error) {
+func (c *cypherExecuter) execTransactionSingle(ctx context.Context, te transactionExecuter, cql string, params map[string]interface{}, configurers []func(*neo4j.TransactionConfig)) (interface{}, error) {
 	var (
-		err    error
-		result neo4j.Result
-		record interface{}
+		err     error
+		result  neo4j.Result
+		record  interface{}
+		stats   *WriteStats
+		profile *Profile
 	)
 
 	if record, err = te(func(tx neo4j.Transaction) (interface{}, error) {
+		stats, profile = newAttemptStats(ctx), newAttemptProfile(ctx)
+		if err = ctx.Err(); err != nil {
+			return nil, err
+		}
 		if result, err = tx.Run(cql, params); err != nil {
 			return nil, err
 		}
-		return result.Single()
-	}); err != nil {
+		record, err := result.Single()
+		if err != nil {
+			return nil, err
+		}
+		return record, consume(result, stats, profile)
+	}, configurers...); err != nil {
 		return nil, err
 	}
 
+	getWriteStats(ctx).merge(stats)
+	getProfile(ctx).merge(profile)
 	return record, nil
 }
 
-func (c *cypherExecuter) exec(dbName string, cql string, params map[string]interface{}, single bool, collect bool) (interface{}, error) {
+func (c *cypherExecuter) exec(ctx context.Context, dbName string, cql string, params map[string]interface{}, single bool, collect bool) (interface{}, error) {
+	if dryRun := getDryRun(ctx); dryRun != nil {
+		dryRun.add(dbName, cql, params)
+		return noRecords(single), nil
+	}
+	if err := c.createSchemas(ctx, dbName); err != nil {
+		return nil, err
+	}
+	profile := getProfile(ctx)
+	cql = profile.plan(cql)
+	if profile.explains() {
+		//Explained statements don't return records
+		if _, err := c.tracer.trace(ctx, dbName, cql, params, func(ctx context.Context) (interface{}, error) {
+			return c.execUntraced(ctx, dbName, cql, params, false, true)
+		}); err != nil {
+			return nil, mapDriverError(err)
+		}
+		return noRecords(single), nil
+	}
+	result, err := c.tracer.trace(ctx, dbName, cql, params, func(ctx context.Context) (interface{}, error) {
+		return c.execUntraced(ctx, dbName, cql, params, single, collect)
+	})
+	return result, mapDriverError(err)
+}
+
+//createSchemas creates the postponed schemas of dbName, if c runs the statements of a session
+func (c *cypherExecuter) createSchemas(ctx context.Context, dbName string) error {
+	if c.registry == nil {
+		return nil
+	}
+	return c.registry.createSchemas(ctx, dbName)
+}
+
+//noRecords returns the result of a statement that isn't run, as returned by single or collect
+func noRecords(single bool) interface{} {
+	if single {
+		return (*db.Record)(nil)
+	}
+	return []*db.Record(nil)
+}
+
+func (c *cypherExecuter) execUntraced(ctx context.Context, dbName string, cql string, params map[string]interface{}, single bool, collect bool) (interface{}, error) {
 	var (
-		result   interface{}
-		txResult neo4j.Result
-		session  neo4j.Session
-		err      error
+		result      interface{}
+		txResult    neo4j.Result
+		session     neo4j.Session
+		configurers []func(*neo4j.TransactionConfig)
+		err         error
 	)
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	if c.transaction != nil {
-		if txResult, err = c.transaction.run(cql, params); err != nil {
+		if txResult, err = c.transaction.run(ctx, cql, params); err != nil {
 			return nil, err
 		}
 
 		if single {
-			return txResult.Single()
+			if result, err = txResult.Single(); err != nil {
+				return nil, err
+			}
+			return result, consume(txResult, getWriteStats(ctx), getProfile(ctx))
 		} else if collect {
-			return txResult.Collect()
+			if result, err = collectWithContext(ctx, txResult); err != nil {
+				return nil, err
+			}
+			return result, consume(txResult, getWriteStats(ctx), getProfile(ctx))
 		}
 		return txResult, nil
 	}
 
+	if configurers, err = transactionConfigurers(ctx); err != nil {
+		return nil, err
+	}
+
 	sessionConfig := neo4j.SessionConfig{
 		AccessMode: c.accessMode,
 	}
@@ -131,25 +225,178 @@ func (c *cypherExecuter) exec(dbName string, cql string, params map[string]inter
 	}
 
 	if single {
-		result, err = c.execTransactionSingle(transactionMode, cql, params)
+		result, err = c.execTransactionSingle(ctx, transactionMode, cql, params, configurers)
 	} else if collect {
-		result, err = c.execTransactionCollect(transactionMode, cql, params)
+		result, err = c.execTransactionCollect(ctx, transactionMode, cql, params, configurers)
 	} else {
-		result, err = c.execTransaction(transactionMode, cql, params)
+		result, err = c.execTransaction(ctx, transactionMode, cql, params, configurers)
+	}
+	if err != nil && ctx.Err() != nil {
+		//The driver error is a consequence of the context being done
+		return nil, ctx.Err()
 	}
 	return result, err
 }
 
-func (c *cypherExecuter) single(dbName string, cql string, params map[string]interface{}) (*db.Record, error) {
-	record, err := c.exec(dbName, cql, params, true, false)
-	return record.(*db.Record), err
+//execWork runs all statements of work in a single transaction: the session transaction when one
+//exists, otherwise a transaction function. A transaction function may be retried by the driver,
+//hence work must not have side effects outside the statements it runs
+func (c *cypherExecuter) execWork(ctx context.Context, dbName string, work func(run statementRunner) error) error {
+	var (
+		session     neo4j.Session
+		configurers []func(*neo4j.TransactionConfig)
+		err         error
+	)
+	if err = ctx.Err(); err != nil {
+		return err
+	}
+
+	if dryRun := getDryRun(ctx); dryRun != nil {
+		return work(func(cql string, params map[string]interface{}) ([]*neo4j.Record, error) {
+			dryRun.add(dbName, cql, params)
+			return nil, nil
+		})
+	}
+	if err = c.createSchemas(ctx, dbName); err != nil {
+		return err
+	}
+
+	if c.transaction != nil {
+		return mapDriverError(work(c.planStatements(ctx, c.traceStatements(ctx, dbName, func(cql string, params map[string]interface{}) ([]*neo4j.Record, error) {
+			result, err := c.transaction.run(ctx, cql, params)
+			if err != nil {
+				return nil, err
+			}
+			records, err := collectWithContext(ctx, result)
+			if err != nil {
+				return nil, err
+			}
+			return records, consume(result, getWriteStats(ctx), getProfile(ctx))
+		}))))
+	}
+
+	if configurers, err = transactionConfigurers(ctx); err != nil {
+		return err
+	}
+
+	sessionConfig := neo4j.SessionConfig{
+		AccessMode: c.accessMode,
+	}
+
+	if dbName != "" {
+		sessionConfig.DatabaseName = dbName
+	}
+
+	session = c.driver.NewSession(sessionConfig)
+	defer session.Close()
+	transactionMode := session.ReadTransaction
+	if c.accessMode == neo4j.AccessModeWrite {
+		transactionMode = session.WriteTransaction
+	}
+
+	var (
+		stats   *WriteStats
+		profile *Profile
+	)
+	_, err = transactionMode(func(tx neo4j.Transaction) (interface{}, error) {
+		stats, profile = newAttemptStats(ctx), newAttemptProfile(ctx)
+		return nil, work(c.planStatements(ctx, c.traceStatements(ctx, dbName, func(cql string, params map[string]interface{}) ([]*neo4j.Record, error) {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			result, err := tx.Run(cql, params)
+			if err != nil {
+				return nil, err
+			}
+			records, err := collectWithContext(ctx, result)
+			if err != nil {
+				return nil, err
+			}
+			return records, consume(result, stats, profile)
+		})))
+	}, configurers...)
+	if err != nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err == nil {
+		getWriteStats(ctx).merge(stats)
+		getProfile(ctx).merge(profile)
+	}
+	return mapDriverError(err)
+}
+
+//traceStatements reports the statements of a unit of work run by run to the tracer
+func (c *cypherExecuter) traceStatements(ctx context.Context, dbName string, run statementRunner) statementRunner {
+	return func(cql string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		result, err := c.tracer.trace(ctx, dbName, cql, params, func(ctx context.Context) (interface{}, error) {
+			return run(cql, params)
+		})
+		records, _ := result.([]*neo4j.Record)
+		return records, err
+	}
+}
+
+//planStatements prefixes the statements of a unit of work run by run to be planned by the profile of ctx, if any
+func (c *cypherExecuter) planStatements(ctx context.Context, run statementRunner) statementRunner {
+	profile := getProfile(ctx)
+	if profile == nil {
+		return run
+	}
+	return func(cql string, params map[string]interface{}) ([]*neo4j.Record, error) {
+		return run(profile.plan(cql), params)
+	}
 }
 
-func (c *cypherExecuter) collect(dbName string, cql string, params map[string]interface{}) ([]*db.Record, error) {
-	record, err := c.exec(dbName, cql, params, false, true)
-	return record.([]*db.Record), err
+func (c *cypherExecuter) single(ctx context.Context, dbName string, cql string, params map[string]interface{}) (*db.Record, error) {
+	result, err := c.exec(ctx, dbName, cql, params, true, false)
+	//result is nil when err isn't
+	record, _ := result.(*db.Record)
+	return record, err
+}
+
+func (c *cypherExecuter) collect(ctx context.Context, dbName string, cql string, params map[string]interface{}) ([]*db.Record, error) {
+	result, err := c.exec(ctx, dbName, cql, params, false, true)
+	records, _ := result.([]*db.Record)
+	return records, err
 }
 
 func (c *cypherExecuter) setTransaction(transaction *transaction) {
 	c.transaction = transaction
 }
+
+//transactionConfigurers maps the deadline of ctx to the driver's transaction timeout so that
+//the database terminates a transaction that outlives its context
+func transactionConfigurers(ctx context.Context) ([]func(*neo4j.TransactionConfig), error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return nil, nil
+	}
+	timeout := time.Until(deadline)
+	if timeout <= 0 {
+		return nil, context.DeadlineExceeded
+	}
+	return []func(*neo4j.TransactionConfig){neo4j.WithTxTimeout(timeout)}, nil
+}
+
+//consume adds the summary of result, whose records are collected, to stats and profile
+func consume(result neo4j.Result, stats *WriteStats, profile *Profile) error {
+	if err := stats.consume(result); err != nil {
+		return err
+	}
+	return profile.consume(result)
+}
+
+//collectWithContext collects the records of result, giving up as soon as ctx is done
+func collectWithContext(ctx context.Context, result neo4j.Result) ([]*neo4j.Record, error) {
+	var records []*neo4j.Record
+	for result.Next() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		records = append(records, result.Record())
+	}
+	if err := result.Err(); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
@@ -23,7 +23,11 @@
 package gogm
 
 import (
+	"context"
 	"reflect"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
 )
@@ -31,16 +35,18 @@ import (
 type deleter struct {
 	cypherExecuter *cypherExecuter
 	store          store
-	eventer        eventer
+	eventer        *eventer
 	registry       *registry
 	graphFactory   graphFactory
 }
 
-func newDeleter(cypherExecuter *cypherExecuter, store store, eventer eventer, registry *registry, graphFactory graphFactory) *deleter {
+func newDeleter(cypherExecuter *cypherExecuter, store store, eventer *eventer, registry *registry, graphFactory graphFactory) *deleter {
 	return &deleter{cypherExecuter, store, eventer, registry, graphFactory}
 }
 
-func (d *deleter) delete(object interface{}, deleteOptions *DeleteOptions) error {
+//delete deletes object from the database. A domain object with a soft delete field is marked as deleted
+//instead, unless hardDelete is set
+func (d *deleter) delete(ctx context.Context, object interface{}, deleteOptions *DeleteOptions, hardDelete bool) error {
 
 	var (
 		value              = reflect.ValueOf(object)
@@ -51,10 +57,12 @@ func (d *deleter) delete(object interface{}, deleteOptions *DeleteOptions) error
 		graphs             []graph
 		record             *neo4j.Record
 		dbName             string = ""
+		depth                     = infiniteDepth
 	)
 
 	if deleteOptions != nil {
 		dbName = deleteOptions.DatabaseName
+		depth = deleteOptions.Depth
 	}
 
 	if graphs, err = d.graphFactory.get(value, map[int]bool{labels: true, relatedGraph: true}, dbName); err != nil {
@@ -79,6 +87,30 @@ func (d *deleter) delete(object interface{}, deleteOptions *DeleteOptions) error
 	if cypherBuilder, err = newCypherBuilder(storedGraph, d.registry, nil, dbName); err != nil {
 		return err
 	}
+
+	if !hardDelete && storedGraph.getValue() != nil && storedGraph.getValue().IsValid() {
+		metadata, err := d.registry.get(storedGraph.getValue().Type(), dbName)
+		if err != nil {
+			return err
+		}
+		if softDeleteName := metadata.getSoftDeleteBackendName(); softDeleteName != emptyString {
+			return d.softDelete(ctx, storedGraph, graphs[0], cypherBuilder, metadata, dbName)
+		}
+	}
+
+	if reflect.TypeOf(storedGraph) == typeOfPrivateNode && storedGraph.getValue() != nil && storedGraph.getValue().IsValid() {
+		var (
+			cascades []interface{}
+			relTypes []string
+		)
+		if cascades, relTypes, err = d.getCascades(storedGraph.getValue().Type(), dbName); err != nil {
+			return err
+		}
+		if len(cascades) > 0 {
+			return d.deleteCascade(ctx, storedGraph, cascades, relTypes, depth, dbName)
+		}
+	}
+
 	delete, deleteParameters, depedencies := cypherBuilder.getDelete(dbName)
 	for _, depedency := range depedencies {
 		var depedencyCypherBuilder graphQueryBuilder
@@ -98,7 +130,7 @@ func (d *deleter) delete(object interface{}, deleteOptions *DeleteOptions) error
 	if cypher != emptyString {
 
 		typeOfGraphToDelete := reflect.TypeOf(storedGraph)
-		for _, eventListener := range d.eventer.eventListeners {
+		for _, eventListener := range d.eventer.listeners() {
 			eventListener.OnPreDelete(event{storedGraph.getValue(), DELETE})
 			if typeOfPrivateNode == typeOfGraphToDelete {
 				for _, relationship := range storedGraph.getRelatedGraphs() {
@@ -108,7 +140,7 @@ func (d *deleter) delete(object interface{}, deleteOptions *DeleteOptions) error
 				}
 			}
 		}
-		if record, err = d.cypherExecuter.single(dbName, cypher, flattenParamters(parameters)); err != nil {
+		if record, err = d.cypherExecuter.single(ctx, dbName, cypher, flattenParamters(parameters)); err != nil {
 			return err
 		}
 		if record != nil {
@@ -125,7 +157,300 @@ func (d *deleter) delete(object interface{}, deleteOptions *DeleteOptions) error
 	return nil
 }
 
-func (d *deleter) deleteAll(object interface{}, deleteOptions *DeleteOptions) error {
+//getCascades returns the relationships through which nodes of type t own nodes, and the nodes they own in turn.
+//A node is owned when it's related to its owner through a relationship field tagged 'cascade:delete'. Each cascade
+//is a parameter of the cascade statement: the type of the relationship, the labels of the owner and of the owned
+//node, and whether the owner is the start node of the relationship
+func (d *deleter) getCascades(t reflect.Type, dbName string) ([]interface{}, []string, error) {
+	var (
+		cascades   []interface{}
+		relTypes   []string
+		err        error
+		isRelType  = map[string]bool{}
+		visited    = map[reflect.Type]bool{t: true}
+		queue      = []reflect.Type{t}
+		addCascade = func(relType string, owner metadata, owned metadata, outgoing bool) {
+			cascades = append(cascades, map[string]interface{}{
+				"type":     relType,
+				"owner":    strings.Split(owner.getStructLabel(), labelsDelim),
+				"owned":    strings.Split(owned.getStructLabel(), labelsDelim),
+				"outgoing": outgoing})
+			if !isRelType[relType] {
+				isRelType[relType] = true
+				relTypes = append(relTypes, relType)
+			}
+		}
+	)
+
+	for len(queue) > 0 {
+		ownerType := queue[0]
+		queue = queue[1:]
+		var ownerMetadata metadata
+		if ownerMetadata, err = d.registry.get(ownerType, dbName); err != nil {
+			return nil, nil, err
+		}
+		owner, isNode := ownerMetadata.(*nodeMetadata)
+		if !isNode {
+			continue
+		}
+		var ownedTypes []reflect.Type
+		for _, structField := range owner.relationshipAStructFields {
+			if !isCascadeDelete(structField) {
+				continue
+			}
+			relationshipField := &field{
+				parent: reflect.New(ownerType.Elem()).Elem(),
+				name:   structField.Name,
+				tag:    getNamespacedTag(structField.Tag)}
+			ownedType := elem(structField.Type)
+			var owned metadata
+			if owned, err = d.registry.get(ownedType, dbName); err != nil {
+				return nil, nil, err
+			}
+			relDirection := relationshipField.getEffectiveDirection()
+			if relDirection == outgoing || relDirection == undirected {
+				addCascade(relationshipField.getRelType(), owner, owned, true)
+			}
+			if relDirection == incoming || relDirection == undirected {
+				addCascade(relationshipField.getRelType(), owner, owned, false)
+			}
+			ownedTypes = append(ownedTypes, ownedType)
+		}
+		for _, structField := range owner.relationshipBStructFields {
+			if !isCascadeDelete(structField) {
+				continue
+			}
+			var relationship metadata
+			if relationship, err = d.registry.get(elem(structField.Type), dbName); err != nil {
+				return nil, nil, err
+			}
+			endpoints := relationship.(*relationshipMetadata).endpoints
+			relType, _ := relationship.getLabel(invalidValue)
+			for _, ownerEndpoint := range []int64{startNode, endNode} {
+				ownedEndpoint := endNode
+				if ownerEndpoint == endNode {
+					ownedEndpoint = startNode
+				}
+				if endpoints[ownerEndpoint].Type != ownerType {
+					continue
+				}
+				ownedType := endpoints[ownedEndpoint].Type
+				var owned metadata
+				if owned, err = d.registry.get(ownedType, dbName); err != nil {
+					return nil, nil, err
+				}
+				addCascade(relType, owner, owned, ownerEndpoint == startNode)
+				ownedTypes = append(ownedTypes, ownedType)
+			}
+		}
+		for _, ownedType := range ownedTypes {
+			if !visited[ownedType] {
+				visited[ownedType] = true
+				queue = append(queue, ownedType)
+			}
+		}
+	}
+	return cascades, relTypes, nil
+}
+
+//deleteCascade deletes node and the nodes it owns up to depth relationships away, with their relationships. The
+//owned nodes are matched in the database, in the transaction deleting them, so that owned nodes that aren't loaded
+//in the session are deleted too. Listeners are notified before the delete of the owned nodes loaded in the session
+//with the relationships owning them
+func (d *deleter) deleteCascade(ctx context.Context, node graph, cascades []interface{}, relTypes []string, depth int, dbName string) error {
+	var (
+		IDs               []int64
+		err               error
+		nodes             []graph
+		notifiedPreDelete = map[graph]bool{}
+		deleted           = map[graph]bool{}
+		deletedGraphs     []graph
+		updatedGraphs     []graph
+	)
+
+	notifyPreDelete := func(node graph) {
+		toNotify := []graph{node}
+		for _, relationship := range node.getRelatedGraphs() {
+			toNotify = append(toNotify, relationship)
+		}
+		for _, g := range toNotify {
+			if notifiedPreDelete[g] || !g.getValue().IsValid() {
+				continue
+			}
+			notifiedPreDelete[g] = true
+			for _, eventListener := range d.eventer.listeners() {
+				eventListener.OnPreDelete(event{g.getValue(), DELETE})
+			}
+		}
+	}
+	notifyPreDelete(node)
+	for _, owned := range d.getStoredOwned(node, cascades, depth) {
+		notifyPreDelete(owned)
+	}
+
+	length := "*1.."
+	if depth > 0 {
+		length += strconv.Itoa(depth)
+	}
+	match := `MATCH (n) WHERE ID(n) = $id
+	MATCH path = (n)-[:` + strings.Join(relTypes, "|") + length + `]-(m)
+	WHERE all(i IN range(0, length(path) - 1) WHERE any(cascade IN $cascades WHERE
+		type(relationships(path)[i]) = cascade.type AND
+		all(label IN cascade.owner WHERE label IN labels(nodes(path)[i])) AND
+		all(label IN cascade.owned WHERE label IN labels(nodes(path)[i + 1])) AND
+		(startNode(relationships(path)[i]) = nodes(path)[i]) = cascade.outgoing))
+	RETURN DISTINCT ID(m)`
+
+	work := func(run statementRunner) error {
+		records, err := run(match, map[string]interface{}{"id": node.getID(), "cascades": cascades})
+		if err != nil {
+			return err
+		}
+		IDs = []int64{node.getID()}
+		for _, record := range records {
+			if ID, isID := record.Values[0].(int64); isID && ID != node.getID() {
+				IDs = append(IDs, ID)
+			}
+		}
+		_, err = run(`MATCH (n) WHERE ID(n) IN $ids DETACH DELETE n RETURN ID(n)`, map[string]interface{}{"ids": IDs})
+		return err
+	}
+
+	if err = d.cypherExecuter.execWork(ctx, dbName, work); err != nil || isDryRun(ctx) {
+		return err
+	}
+
+	for _, ID := range IDs {
+		if owned := d.store.node(ID); owned != nil {
+			nodes = append(nodes, owned)
+		}
+	}
+
+	//Update the store before notifying, as notifying deleted graphs resets their IDs
+	for _, node := range nodes {
+		nodeDeletedGraphs, nodeUpdatedGraphs := d.store.delete(node, dbName)
+		for _, deletedGraph := range nodeDeletedGraphs {
+			deleted[deletedGraph] = true
+		}
+		deletedGraphs = append(deletedGraphs, nodeDeletedGraphs...)
+		updatedGraphs = append(updatedGraphs, nodeUpdatedGraphs...)
+	}
+	notifiedUpdate := map[graph]bool{}
+	for _, updatedGraph := range updatedGraphs {
+		if updatedGraph != nil && !deleted[updatedGraph] && !notifiedUpdate[updatedGraph] {
+			notifiedUpdate[updatedGraph] = true
+			notifyPostDelete(d.eventer, updatedGraph, UPDATE)
+		}
+	}
+	for _, deletedGraph := range deletedGraphs {
+		notifyPostDelete(d.eventer, deletedGraph, DELETE)
+	}
+	return nil
+}
+
+//getStoredOwned returns the nodes of the store that node owns up to depth relationships away, following the
+//relationships of the store as the cascade statement follows those of the database
+func (d *deleter) getStoredOwned(node graph, cascades []interface{}, depth int) []graph {
+	var (
+		owned   []graph
+		visited = map[int64]bool{node.getID(): true}
+		owners  = []graph{node}
+	)
+	for distance := 0; len(owners) > 0 && (depth <= 0 || distance < depth); distance++ {
+		var next []graph
+		for _, owner := range owners {
+			for _, relatedGraph := range owner.getRelatedGraphs() {
+				r, isRelationship := relatedGraph.(*relationship)
+				if !isRelationship {
+					continue
+				}
+				ownedNode, outgoing := r.nodes[endNode], true
+				if ownedNode.getID() == owner.getID() {
+					ownedNode, outgoing = r.nodes[startNode], false
+				}
+				storedNode := d.store.node(ownedNode.getID())
+				if visited[ownedNode.getID()] || storedNode == nil || !isCascaded(cascades, r.getType(), owner.getLabel(), storedNode.getLabel(), outgoing) {
+					continue
+				}
+				visited[ownedNode.getID()] = true
+				owned = append(owned, storedNode)
+				next = append(next, storedNode)
+			}
+		}
+		owners = next
+	}
+	return owned
+}
+
+//isCascaded returns whether a node labeled ownerLabel owns a node labeled ownedLabel through a relationship of type
+//relType, outgoing from the owner or not
+func isCascaded(cascades []interface{}, relType string, ownerLabel string, ownedLabel string, outgoing bool) bool {
+	hasLabels := func(label string, labels []string) bool {
+		nodeLabels := map[string]bool{}
+		for _, nodeLabel := range strings.Split(label, labelsDelim) {
+			nodeLabels[nodeLabel] = true
+		}
+		for _, label := range labels {
+			if !nodeLabels[label] {
+				return false
+			}
+		}
+		return true
+	}
+	for _, cascade := range cascades {
+		cascade := cascade.(map[string]interface{})
+		if cascade["type"] == relType && cascade["outgoing"] == outgoing &&
+			hasLabels(ownerLabel, cascade["owner"].([]string)) && hasLabels(ownedLabel, cascade["owned"].([]string)) {
+			return true
+		}
+	}
+	return false
+}
+
+func isCascadeDelete(structField reflect.StructField) bool {
+	for _, cascade := range getNamespacedTag(structField.Tag).get(cascadeTag) {
+		if cascade == "delete" {
+			return true
+		}
+	}
+	return false
+}
+
+//softDelete sets the soft delete property of storedGraph. storedGraph stays in the store, but isn't loaded
+//anymore unless soft deleted objects are included in the load options
+func (d *deleter) softDelete(ctx context.Context, storedGraph graph, g graph, cypherBuilder graphQueryBuilder, metadata metadata, dbName string) error {
+	var (
+		softDeleteName  = metadata.getSoftDeleteBackendName()
+		softDeleteField = metadata.getPropertyStructFields()[softDeleteName]
+		softDeleted     = getSoftDeletedValue(softDeleteField.Type, time.Now())
+		record          *neo4j.Record
+		err             error
+	)
+
+	for _, eventListener := range d.eventer.listeners() {
+		eventListener.OnPreDelete(event{storedGraph.getValue(), DELETE})
+	}
+
+	cypher, parameters := cypherBuilder.getSoftDelete(softDeleteName, softDeleted.Interface())
+	if record, err = d.cypherExecuter.single(ctx, dbName, cypher, parameters); err != nil {
+		return err
+	}
+	if record != nil {
+		for _, deleted := range []graph{storedGraph, g} {
+			if deleted.getValue() != nil && deleted.getValue().IsValid() {
+				deleted.getValue().Elem().FieldByName(softDeleteField.Name).Set(softDeleted)
+			}
+		}
+		//Keep the stored properties in sync so that the soft delete isn't seen as a change on save
+		storedGraph.getProperties()[softDeleteName] = softDeleted.Interface()
+		d.eventer.post(func(eventListener EventListener) {
+			eventListener.OnPostDelete(event{storedGraph.getValue(), DELETE})
+		})
+	}
+	return nil
+}
+
+func (d *deleter) deleteAll(ctx context.Context, object interface{}, deleteOptions *DeleteOptions) error {
 	var (
 		value   = reflect.ValueOf(object)
 		graphs  []graph
@@ -149,7 +474,7 @@ func (d *deleter) deleteAll(object interface{}, deleteOptions *DeleteOptions) er
 	cypher, parameter := cypherBuilder.getDeleteAll()
 
 	if cypher != emptyString {
-		if records, err = d.cypherExecuter.collect(dbName, cypher, parameter); err != nil {
+		if records, err = d.cypherExecuter.collect(ctx, dbName, cypher, parameter); err != nil {
 			return err
 		}
 		for _, record := range records {
@@ -167,7 +492,7 @@ func (d *deleter) deleteAll(object interface{}, deleteOptions *DeleteOptions) er
 	return nil
 }
 
-func (d *deleter) purgeDatabase(deleteOptions *DeleteOptions) error {
+func (d *deleter) purgeDatabase(ctx context.Context, deleteOptions *DeleteOptions) error {
 	var err error
 	var dbName string = ""
 
@@ -175,7 +500,7 @@ func (d *deleter) purgeDatabase(deleteOptions *DeleteOptions) error {
 		dbName = deleteOptions.DatabaseName
 	}
 
-	if _, err = d.cypherExecuter.exec(dbName, "MATCH (n) DETACH DELETE n", nil, false, false); err != nil {
+	if _, err = d.cypherExecuter.collect(ctx, dbName, "MATCH (n) DETACH DELETE n", nil); err != nil || isDryRun(ctx) {
 		return err
 	}
 	for _, deletedGraph := range d.store.purge(dbName) {
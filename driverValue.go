@@ -47,13 +47,16 @@ func sliceAsType(driverValue interface{}, structFieldType reflect.Type) interfac
 		ptr := reflect.New(slice.Type())
 		ptr.Elem().Set(slice)
 		for i := 0; i < values.Len(); i++ {
-			ptr.Elem().Set(reflect.Append(ptr.Elem(), reflect.ValueOf(driverValueAsType(values.Index(i).Interface(), structFieldType.Elem()))))
+			value := reflect.ValueOf(driverValueAsType(values.Index(i).Interface(), structFieldType.Elem()))
+			if !value.IsValid() || !value.Type().AssignableTo(structFieldType.Elem()) {
+				//Left to unloadGraphProperties to report
+				return driverValue
+			}
+			ptr.Elem().Set(reflect.Append(ptr.Elem(), value))
 		}
 		return ptr.Elem().Interface()
 	case reflect.Ptr:
-		ptr := reflect.New(structFieldType.Elem())
-		ptr.Elem().Set(reflect.ValueOf(sliceAsType(driverValue, structFieldType.Elem())))
-		return ptr.Interface()
+		return ptrAsType(driverValue, sliceAsType(driverValue, structFieldType.Elem()), structFieldType)
 	default:
 		return driverValue
 	}
@@ -62,9 +65,7 @@ func sliceAsType(driverValue interface{}, structFieldType reflect.Type) interfac
 func valueAsType(driverValue interface{}, structFieldType reflect.Type) interface{} {
 	switch structFieldType.Kind() {
 	case reflect.Ptr:
-		ptr := reflect.New(structFieldType.Elem())
-		ptr.Elem().Set(reflect.ValueOf(valueAsType(driverValue, structFieldType.Elem())))
-		return ptr.Interface()
+		return ptrAsType(driverValue, valueAsType(driverValue, structFieldType.Elem()), structFieldType)
 	default:
 		return driverValue
 	}
@@ -81,9 +82,7 @@ func int64AsType(driverValue interface{}, structFieldType reflect.Type) interfac
 	case reflect.Int32:
 		return int32(driverValue.(int64))
 	case reflect.Ptr:
-		ptr := reflect.New(structFieldType.Elem())
-		ptr.Elem().Set(reflect.ValueOf(int64AsType(driverValue, structFieldType.Elem())))
-		return ptr.Interface()
+		return ptrAsType(driverValue, int64AsType(driverValue, structFieldType.Elem()), structFieldType)
 	default:
 		return driverValue
 	}
@@ -94,10 +93,20 @@ func float64AsType(driverValue interface{}, structFieldType reflect.Type) interf
 	case reflect.Float32:
 		return float32(driverValue.(float64))
 	case reflect.Ptr:
-		ptr := reflect.New(structFieldType.Elem())
-		ptr.Elem().Set(reflect.ValueOf(float64AsType(driverValue, structFieldType.Elem())))
-		return ptr.Interface()
+		return ptrAsType(driverValue, float64AsType(driverValue, structFieldType.Elem()), structFieldType)
 	default:
 		return driverValue
 	}
 }
+
+//ptrAsType returns a pointer of type structFieldType to value, driverValue converted to the pointed type. driverValue
+//is returned as is when value can't be pointed to, for unloadGraphProperties to report
+func ptrAsType(driverValue interface{}, value interface{}, structFieldType reflect.Type) interface{} {
+	v := reflect.ValueOf(value)
+	if !v.IsValid() || !v.Type().AssignableTo(structFieldType.Elem()) {
+		return driverValue
+	}
+	ptr := reflect.New(structFieldType.Elem())
+	ptr.Elem().Set(v)
+	return ptr.Interface()
+}
@@ -0,0 +1,90 @@
+// MIT License
+//
+// Copyright (c) 2022 pmadhav
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package gogm
+
+import (
+	"context"
+	"reflect"
+)
+
+//DryRun collects the statements an operation would run instead of running them, leaving the session store and the
+//domain objects unchanged. Pre save and pre delete events are delivered as the statements are built, post events
+//aren't
+type DryRun struct {
+	Statements []Statement
+}
+
+//Statement is a statement with its flattened parameters
+type Statement struct {
+	DatabaseName string
+	Cypher       string
+	Parameters   map[string]interface{}
+}
+
+type dryRunKey struct{}
+
+//withDryRun returns a context collecting the statements run with it into dryRun, reset first, instead of running them
+func withDryRun(ctx context.Context, dryRun *DryRun) context.Context {
+	if dryRun == nil {
+		return ctx
+	}
+	dryRun.Statements = nil
+	return context.WithValue(ctx, dryRunKey{}, dryRun)
+}
+
+//getDryRun returns the dry run of ctx, nil when statements run with ctx are run
+func getDryRun(ctx context.Context) *DryRun {
+	dryRun, _ := ctx.Value(dryRunKey{}).(*DryRun)
+	return dryRun
+}
+
+//isDryRun tells whether the statements run with ctx have neither effects nor records, being collected by a dry run
+//or explained by a profile
+func isDryRun(ctx context.Context) bool {
+	return getDryRun(ctx) != nil || getProfile(ctx).explains()
+}
+
+func (d *DryRun) add(dbName string, cql string, params map[string]interface{}) {
+	d.Statements = append(d.Statements, Statement{dbName, cql, params})
+}
+
+//domainObjects keeps copies of domain objects, by pointer, so that they can be restored after a dry run
+type domainObjects map[interface{}]reflect.Value
+
+func (d domainObjects) keep(g graph) {
+	if g.getValue() == nil || !g.getValue().IsValid() || g.getValue().IsNil() {
+		return
+	}
+	pointer := g.getValue().Interface()
+	if _, isKept := d[pointer]; !isKept {
+		kept := reflect.New(g.getValue().Type().Elem()).Elem()
+		kept.Set(g.getValue().Elem())
+		d[pointer] = kept
+	}
+}
+
+func (d domainObjects) restore() {
+	for pointer, kept := range d {
+		reflect.ValueOf(pointer).Elem().Set(kept)
+	}
+}
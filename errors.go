@@ -0,0 +1,108 @@
+// MIT License
+//
+// Copyright (c) 2022 pmadhav
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package gogm
+
+import (
+	"errors"
+	"reflect"
+
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+)
+
+//ErrOptimisticLock is returned by Save when a versioned domain object was modified in the database
+//since it was loaded. The session store is left untouched; reload the object and apply the changes again
+var ErrOptimisticLock = errors.New("optimistic lock failed: the object was modified or deleted since it was loaded")
+
+//ErrNotFound is returned when an object to save no longer exists in the database, and by Repository.FindByID when
+//no object has the ID. Session.Load, Session.QueryForObject and the other loads keep returning a nil object without
+//an error when nothing matches, hence errors.Is(err, ErrNotFound) only applies to saves and FindByID
+var ErrNotFound = errors.New("not found")
+
+//ErrMultipleResults is returned when a single object is loaded or queried and the database returns several
+var ErrMultipleResults = errors.New("multiple results")
+
+//ErrConstraintViolation is returned when a statement violates a constraint of the database. The error of the
+//driver is wrapped, use errors.As with a *neo4j.Neo4jError to get its code and message
+var ErrConstraintViolation = errors.New("constraint violation")
+
+//ErrTransactionState is returned when a transaction is begun, ended or closed in a state that doesn't allow it,
+//e.g. when a session already has a transaction
+var ErrTransactionState = errors.New("invalid transaction state")
+
+//ErrMapping is returned when an entity of the database can't be mapped to a domain object. Type is the type of the
+//domain object, nil when no registered type maps the entity, and Field the name of the field that can't be set, if any
+type ErrMapping struct {
+	Type   reflect.Type
+	Field  string
+	Reason string
+}
+
+func (e *ErrMapping) Error() string {
+	if e.Type == nil {
+		return "Can't map to a domain object: " + e.Reason
+	}
+	if e.Field == emptyString {
+		return "Can't map to domain object '" + e.Type.String() + "': " + e.Reason
+	}
+	return "Can't map field '" + e.Field + "' of domain object '" + e.Type.String() + "': " + e.Reason
+}
+
+//constraintViolationCodes are the codes of the errors of the database raised by constraint violations
+var constraintViolationCodes = map[string]bool{
+	"Neo.ClientError.Schema.ConstraintValidationFailed": true,
+	"Neo.ClientError.Schema.ConstraintViolation":        true}
+
+//kindError is an error reported by errors.Is as being of kind, e.g. ErrTransactionState, wrapping the error that
+//occurred
+type kindError struct {
+	kind error
+	err  error
+}
+
+func newKindError(kind error, message string) error {
+	return &kindError{kind, errors.New(message)}
+}
+
+func (e *kindError) Error() string {
+	return e.err.Error()
+}
+
+func (e *kindError) Is(target error) bool {
+	return target == e.kind
+}
+
+func (e *kindError) Unwrap() error {
+	return e.err
+}
+
+//mapDriverError reports errors of the driver raised by constraint violations as ErrConstraintViolation
+func mapDriverError(err error) error {
+	var neo4jError *neo4j.Neo4jError
+	if err == nil || errors.Is(err, ErrConstraintViolation) || !errors.As(err, &neo4jError) {
+		return err
+	}
+	if constraintViolationCodes[neo4jError.Code] {
+		return &kindError{ErrConstraintViolation, err}
+	}
+	return err
+}
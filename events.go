@@ -22,7 +22,10 @@
 
 package gogm
 
-import "reflect"
+import (
+	"reflect"
+	"sync"
+)
 
 type lifeCycle int
 
@@ -68,8 +71,15 @@ type EventListener interface {
 	OnPostDelete(event Event)
 }
 
+//eventer delivers events to the event listeners. It's safe for concurrent use
 type eventer struct {
 	eventListeners map[reflect.Value]EventListener
+
+	//deferred are the post save and post delete events fired while a transaction is open. They are
+	//delivered once the transaction commits and discarded when it rolls back
+	deferred   bool
+	deliveries []func(EventListener)
+	mu         sync.RWMutex
 }
 
 func newEventer() *eventer {
@@ -77,12 +87,73 @@ func newEventer() *eventer {
 		eventListeners: map[reflect.Value]EventListener{}}
 }
 
+//listeners returns the event listeners registered when it's called
+func (e *eventer) listeners() []EventListener {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	eventListeners := make([]EventListener, 0, len(e.eventListeners))
+	for _, eventListener := range e.eventListeners {
+		eventListeners = append(eventListeners, eventListener)
+	}
+	return eventListeners
+}
+
+//post delivers an event to the event listeners, unless a transaction is open in which case the delivery
+//is deferred until the transaction ends
+func (e *eventer) post(delivery func(EventListener)) {
+	e.mu.Lock()
+	if e.deferred {
+		e.deliveries = append(e.deliveries, delivery)
+		e.mu.Unlock()
+		return
+	}
+	e.mu.Unlock()
+
+	for _, eventListener := range e.listeners() {
+		delivery(eventListener)
+	}
+}
+
+func (e *eventer) deferEvents() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.deferred = true
+	e.deliveries = nil
+}
+
+func (e *eventer) flushEvents() {
+	e.mu.Lock()
+	deliveries := e.deliveries
+	e.deferred = false
+	e.deliveries = nil
+	e.mu.Unlock()
+
+	eventListeners := e.listeners()
+	for _, delivery := range deliveries {
+		for _, eventListener := range eventListeners {
+			delivery(eventListener)
+		}
+	}
+}
+
+func (e *eventer) discardEvents() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.deferred = false
+	e.deliveries = nil
+}
+
 func (e *eventer) registerEventListener(eventListener EventListener) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
 	e.eventListeners[reflect.ValueOf(eventListener)] = eventListener
 	return nil
 }
 
 func (e *eventer) disposeEventListener(eventListener EventListener) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
 	delete(e.eventListeners, reflect.ValueOf(eventListener))
 	return nil
 }
@@ -0,0 +1,170 @@
+// MIT License
+//
+// Copyright (c) 2022 pmadhav
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package gogm
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+//Operator is the comparison applied by a Filter
+type Operator int
+
+const (
+	EQUALS Operator = iota
+	NOT_EQUALS
+	GREATER_THAN
+	GREATER_THAN_OR_EQUALS
+	LESS_THAN
+	LESS_THAN_OR_EQUALS
+	IN
+	STARTS_WITH
+	IS_NULL
+	IS_NOT_NULL
+)
+
+var operators = map[Operator]string{
+	EQUALS:                 "=",
+	NOT_EQUALS:             "<>",
+	GREATER_THAN:           ">",
+	GREATER_THAN_OR_EQUALS: ">=",
+	LESS_THAN:              "<",
+	LESS_THAN_OR_EQUALS:    "<=",
+	IN:                     "IN",
+	STARTS_WITH:            "STARTS WITH",
+	IS_NULL:                "IS NULL",
+	IS_NOT_NULL:            "IS NOT NULL"}
+
+//Filter restricts the root entities loaded by LoadAll to those whose Property compares to Value.
+//Property is either the name of a struct field or the name of the property in the database.
+//Value is ignored by IS_NULL and IS_NOT_NULL
+type Filter struct {
+	Property string
+	Operator Operator
+	Value    interface{}
+}
+
+//Order sorts the root entities loaded by LoadAll on Property
+type Order struct {
+	Property   string
+	Descending bool
+}
+
+type loadAllClauses struct {
+	filters    []string
+	orderBy    string
+	pagination string
+	parameters map[string]interface{}
+}
+
+//getLoadAllClauses compiles the filters, ordering and pagination of lo into parameterized Cypher
+//on the entity bound to variable
+func getLoadAllClauses(variable string, metadata metadata, lo *LoadOptions) (*loadAllClauses, error) {
+	var (
+		err          error
+		propertyName string
+		clauses      = &loadAllClauses{parameters: map[string]interface{}{}}
+	)
+
+	for index, filter := range lo.Filters {
+		if propertyName, err = getFilterPropertyName(metadata, filter.Property); err != nil {
+			return nil, err
+		}
+		operator, ok := operators[filter.Operator]
+		if !ok {
+			return nil, errors.New("Unknown filter operator " + strconv.Itoa(int(filter.Operator)) + " on property '" + filter.Property + "'")
+		}
+		property := variable + `.` + propertyName
+		if filter.Operator == IS_NULL || filter.Operator == IS_NOT_NULL {
+			clauses.filters = append(clauses.filters, property+` `+operator)
+			continue
+		}
+		filterCQLRef := `filter` + strconv.Itoa(index)
+		clauses.filters = append(clauses.filters, property+` `+operator+` $`+filterCQLRef)
+		clauses.parameters[filterCQLRef] = filter.Value
+	}
+
+	if !lo.IncludeSoftDeleted {
+		if filter, parameters := getSoftDeleteFilter(variable, metadata); filter != emptyString {
+			clauses.filters = append(clauses.filters, filter)
+			for key, value := range parameters {
+				clauses.parameters[key] = value
+			}
+		}
+	}
+
+	var orderBy []string
+	for _, order := range lo.OrderBy {
+		if propertyName, err = getFilterPropertyName(metadata, order.Property); err != nil {
+			return nil, err
+		}
+		direction := ` ASC`
+		if order.Descending {
+			direction = ` DESC`
+		}
+		orderBy = append(orderBy, variable+`.`+propertyName+direction)
+	}
+	if len(orderBy) > 0 {
+		clauses.orderBy = `ORDER BY ` + strings.Join(orderBy, `, `) + `
+	`
+	}
+
+	if lo.Skip < 0 || lo.Limit < 0 {
+		return nil, errors.New("Skip and Limit of load options can't be negative")
+	}
+	if lo.Skip > 0 {
+		clauses.pagination += `SKIP $skip
+	`
+		clauses.parameters["skip"] = lo.Skip
+	}
+	if lo.Limit > 0 {
+		clauses.pagination += `LIMIT $limit
+	`
+		clauses.parameters["limit"] = lo.Limit
+	}
+
+	return clauses, nil
+}
+
+//getFilterPropertyName resolves property, a struct field name or a backend property name, to the
+//quoted backend property name. Only properties known to metadata are accepted so that a property
+//can't be used to inject Cypher
+func getFilterPropertyName(metadata metadata, property string) (string, error) {
+	structFields := metadata.getPropertyStructFields()
+	backendName := emptyString
+	if structFields[property] != nil {
+		backendName = property
+	} else {
+		for name, structField := range structFields {
+			if structField.Name == property {
+				backendName = name
+				break
+			}
+		}
+	}
+	if backendName == emptyString {
+		return emptyString, errors.New("Property '" + property + "' isn't a property of domain object " + metadata.getType().String())
+	}
+	return "`" + strings.ReplaceAll(backendName, "`", "``") + "`", nil
+}
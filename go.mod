@@ -1,11 +1,16 @@
 module github.com/pmadhav/neo4j-go-ogm
 
-go 1.16
+go 1.18
 
 require (
-	github.com/kr/text v0.2.0 // indirect
 	github.com/neo4j/neo4j-go-driver/v4 v4.4.3
 	github.com/onsi/gomega v1.19.0
+)
+
+require (
+	github.com/kr/text v0.2.0 // indirect
 	golang.org/x/net v0.0.0-20220706163947-c90051bbdb60 // indirect
+	golang.org/x/text v0.3.7 // indirect
 	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c // indirect
+	gopkg.in/yaml.v2 v2.4.0 // indirect
 )
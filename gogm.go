@@ -23,14 +23,18 @@
 package gogm
 
 import (
+	"context"
+	"errors"
 	"math"
 	"reflect"
+	"sync"
 
 	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
 )
 
 const (
 	idPropertyName            = "id"
+	versionPropertyName       = "version"
 	labelsDelim               = ":"
 	emptyString               = ""
 	spaceString               = " "
@@ -97,44 +101,50 @@ var logLevels = map[LogLevel]neo4j.LogLevel{
 	DEBUG:   neo4j.DEBUG,
 }
 
-//Gogm is an instance of the OGM
+//Gogm is an instance of the OGM. It's safe for concurrent use, so that goroutines can create their own sessions.
+//The metadata of domain objects is computed once per instance and shared by its sessions
 type Gogm struct {
-	config *Config
-	driver neo4j.Driver
+	config   *Config
+	driver   neo4j.Driver
+	registry *registry
+	tracer   *tracer
+	driverMu sync.Mutex
 }
 
 //New creates a new instance of the OGM
 func New(config *Config) *Gogm {
 	return &Gogm{
-		config,
-		nil,
+		config: config,
+		tracer: newTracer(config),
 	}
 }
 
 //NewSession creates a new session on an OGM instance
 func (g *Gogm) NewSession(isWriteMode bool) (Session, error) {
 
-	var err error
-	var accessMode neo4j.AccessMode = neo4j.AccessModeRead
+	var (
+		err        error
+		driver     neo4j.Driver
+		registry   *registry
+		accessMode neo4j.AccessMode = neo4j.AccessModeRead
+	)
 	if isWriteMode {
 		accessMode = neo4j.AccessModeWrite
 	}
 
-	if g.driver == nil {
-		if g.driver, err = g.config.getDriver(); err != nil {
-			return nil, err
-		}
+	if driver, registry, err = g.getDriver(); err != nil {
+		return nil, err
 	}
 
-	cypherExecutor := newCypherExecuter(g.driver, accessMode, nil)
-	registry := newRegistry(*cypherExecutor)
+	cypherExecutor := newCypherExecuter(driver, accessMode, g.tracer)
+	cypherExecutor.registry = registry
 	graphFactory := newGraphFactory(registry)
 	transactioner := newTransactioner(accessMode)
 	eventer := newEventer()
 	store := newstore(registry)
-	saver := newSaver(cypherExecutor, store, *eventer, registry, *graphFactory)
-	loader := newLoader(cypherExecutor, store, *eventer, registry, *graphFactory, g.config.AllowCyclicRef)
-	deleter := newDeleter(cypherExecutor, store, *eventer, registry, *graphFactory)
+	saver := newSaver(cypherExecutor, store, eventer, registry, *graphFactory)
+	loader := newLoader(cypherExecutor, store, eventer, registry, *graphFactory, g.config.AllowCyclicRef)
+	deleter := newDeleter(cypherExecutor, store, eventer, registry, *graphFactory)
 	queryer := newQueryer(cypherExecutor, *graphFactory, registry)
 
 	return &sessionImpl{
@@ -146,10 +156,86 @@ func (g *Gogm) NewSession(isWriteMode bool) (Session, error) {
 		transactioner,
 		store,
 		registry,
-		g.driver,
+		driver,
 		eventer}, nil
 }
 
+//Register computes and validates the metadata of the domain objects, and creates their constraints and indexes.
+//objects are domain objects, pointers to domain objects or slices of them, e.g. &Movie{}. Registering the domain
+//objects at startup surfaces mapping errors early instead of on the first session using them
+func (g *Gogm) Register(objects ...interface{}) error {
+	var (
+		registry *registry
+		err      error
+	)
+	if _, registry, err = g.getDriver(); err != nil {
+		return err
+	}
+	for _, object := range objects {
+		if object == nil {
+			return errors.New("can't register a nil object")
+		}
+		t := elem(reflect.TypeOf(object))
+		if t.Kind() == reflect.Struct {
+			t = reflect.PtrTo(t)
+		}
+		if _, err = registry.get(t, emptyString); err != nil {
+			return err
+		}
+	}
+	return registry.createSchemas(context.Background(), emptyString)
+}
+
+//SchemaDiff compares the constraints and indexes declared by the registered domain objects with those of the
+//database dbName. Only domain objects used by a session or registered with Register are declared, so register them
+//all before diffing, e.g. in a deployment gate
+func (g *Gogm) SchemaDiff(dbName string) (SchemaReport, error) {
+	return g.SchemaDiffCtx(context.Background(), dbName)
+}
+
+func (g *Gogm) SchemaDiffCtx(ctx context.Context, dbName string) (SchemaReport, error) {
+	_, registry, err := g.getDriver()
+	if err != nil {
+		return SchemaReport{}, err
+	}
+	report, _, err := registry.diffSchema(ctx, dbName)
+	return report, err
+}
+
+//SchemaApply creates the constraints and indexes of SchemaDiff which are missing from the database dbName. Depending
+//on schemaApplyOptions, it also drops the extra ones and replaces the mismatched ones. It returns the report of the
+//differences found before applying them
+func (g *Gogm) SchemaApply(dbName string, schemaApplyOptions *SchemaApplyOptions) (SchemaReport, error) {
+	return g.SchemaApplyCtx(context.Background(), dbName, schemaApplyOptions)
+}
+
+func (g *Gogm) SchemaApplyCtx(ctx context.Context, dbName string, schemaApplyOptions *SchemaApplyOptions) (SchemaReport, error) {
+	_, registry, err := g.getDriver()
+	if err != nil {
+		return SchemaReport{}, err
+	}
+	return registry.applySchema(ctx, dbName, schemaApplyOptions)
+}
+
+//getDriver returns the driver and the registry of the instance, creating them on first use
+func (g *Gogm) getDriver() (neo4j.Driver, *registry, error) {
+	var err error
+
+	g.driverMu.Lock()
+	defer g.driverMu.Unlock()
+
+	if g.driver == nil {
+		if g.driver, err = g.config.getDriver(); err != nil {
+			return nil, nil, err
+		}
+	}
+	if g.registry == nil {
+		//Constraints and indexes are created in write mode, whatever the access mode of the sessions
+		g.registry = newRegistry(*newCypherExecuter(g.driver, neo4j.AccessModeWrite, g.tracer), g.config.SchemaDialect)
+	}
+	return g.driver, g.registry, nil
+}
+
 func (conf *Config) getDriver() (neo4j.Driver, error) {
 	var (
 		err    error
@@ -157,7 +243,9 @@ func (conf *Config) getDriver() (neo4j.Driver, error) {
 	)
 
 	if driver, err = neo4j.NewDriver(conf.URI, neo4j.BasicAuth(conf.Username, conf.Password, ""), func(config *neo4j.Config) {
-		if conf.LogLevel != NONE {
+		if conf.LogLevel != NONE && conf.Logger != nil {
+			config.Log = &driverLogger{conf.Logger, conf.LogLevel}
+		} else if conf.LogLevel != NONE {
 			config.Log = neo4j.ConsoleLogger(logLevels[conf.LogLevel])
 		}
 	}); err != nil {
@@ -23,24 +23,31 @@
 package gogm_test
 
 import (
+	"context"
+	"errors"
 	"math"
+	"reflect"
 	"sort"
+	"strconv"
+	"sync"
 	"testing"
+	"testing/fstest"
 	"time"
 
 	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
 
 	. "github.com/onsi/gomega"
 	gogm "github.com/pmadhav/neo4j-go-ogm"
+	"github.com/pmadhav/neo4j-go-ogm/migrations"
 	. "github.com/pmadhav/neo4j-go-ogm/tests/models"
 )
 
 var config = &gogm.Config{
-	"bolt://localhost:7687",
-	"neo4j",
-	"Pass1234",
-	gogm.DEBUG,
-	true,
+	URI:            "bolt://localhost:7687",
+	Username:       "neo4j",
+	Password:       "Pass1234",
+	LogLevel:       gogm.DEBUG,
+	AllowCyclicRef: true,
 }
 
 var dbName string = ""
@@ -989,18 +996,28 @@ func TestTransactions(t *testing.T) {
 	g.Expect(tx.RollBack()).NotTo(HaveOccurred())
 	g.Expect(tx.Close()).NotTo(HaveOccurred())
 
-	var loadedN0 *Node0
-	lo := gogm.NewLoadOptions(dbName)
-	lo.Depth = 0
-	g.Expect(session.Load(&loadedN0, *n0.ID, lo)).NotTo(HaveOccurred())
-
 	g.Expect(n0.Name).To(Equal("0Update"))
-	g.Expect(loadedN0.Name).To(Equal(n0.Name))
-	g.Expect(*loadedN0.N1.N2.N3.ID).To(Equal(*n3.ID), "Store cache still holds state of rolled back transcation")
+	g.Expect(n3.ID).To(BeNil(), "Rolling back restores the IDs of the objects saved in the transaction")
+	g.Expect(n4.ID).To(BeNil())
+
+	//The store holds the committed state, hence the rolled back changes are saved again
+	g.Expect(session.Save(&n2, gogm.NewSaveOptions(dbName, 0))).NotTo(HaveOccurred())
+	count, err := session.Count(loadOptions, "MATCH (n:Node2) WHERE n.Name = $name RETURN COUNT(n)", map[string]interface{}{"name": "2Update"})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(count).To(Equal(int64(1)))
 
 	g.Expect(session.Reload(loadOptions, &n0)).NotTo(HaveOccurred(), "Reload to sycn runtime objects with backend")
 	g.Expect(n0.Name).To(Equal("0"))
-	g.Expect(*n3.ID).To(Equal(deletedID), "n3 gets deleted as it was rolled back. n3 can't ever be saved again. Must create new instance to save")
+
+	//Deleted objects get back their ID on rolling back
+	n2ID := *n2.ID
+	tx, err = session.BeginTransaction(dbName)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(session.Delete(&n2, deleteOptions)).NotTo(HaveOccurred())
+	g.Expect(*n2.ID).To(Equal(deletedID))
+	g.Expect(tx.RollBack()).NotTo(HaveOccurred())
+	g.Expect(tx.Close()).NotTo(HaveOccurred())
+	g.Expect(*n2.ID).To(Equal(n2ID))
 
 	//Testing committing
 	n3 = &Node3{}
@@ -1472,3 +1489,1311 @@ func TestMoreThanOneEntityWithSameLabel(t *testing.T) {
 	g.Expect(session.PurgeDatabase(deleteOptions)).NotTo(HaveOccurred())
 	g.Expect(session.DisposeEventListener(eventListener)).NotTo(HaveOccurred())
 }
+
+func TestContextCancellation(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(session.PurgeDatabase(deleteOptions)).NotTo(HaveOccurred())
+	g.Expect(session.DisposeEventListener(eventListener)).NotTo(HaveOccurred())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	simpleNode := &SimpleNode{}
+	g.Expect(session.SaveCtx(ctx, &simpleNode, saveOptions)).To(MatchError(context.Canceled))
+	g.Expect(simpleNode.ID).To(BeNil())
+
+	g.Expect(session.SaveCtx(context.Background(), &simpleNode, saveOptions)).NotTo(HaveOccurred())
+
+	var loaded *SimpleNode
+	g.Expect(session.Clear()).NotTo(HaveOccurred())
+	g.Expect(session.LoadCtx(ctx, &loaded, *simpleNode.ID, loadOptions)).To(MatchError(context.Canceled))
+	g.Expect(loaded).To(BeNil())
+
+	deadlineCtx, cancelDeadline := context.WithTimeout(context.Background(), time.Minute)
+	defer cancelDeadline()
+	g.Expect(session.LoadCtx(deadlineCtx, &loaded, *simpleNode.ID, loadOptions)).NotTo(HaveOccurred())
+	g.Expect(*loaded.ID).To(Equal(*simpleNode.ID))
+
+	_, err := session.BeginTransactionCtx(ctx, dbName)
+	g.Expect(err).To(MatchError(context.Canceled))
+
+	g.Expect(session.PurgeDatabase(deleteOptions)).NotTo(HaveOccurred())
+	g.Expect(session.DisposeEventListener(eventListener)).NotTo(HaveOccurred())
+}
+
+func TestLoadAllWithFiltersSortAndPagination(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(session.PurgeDatabase(deleteOptions)).NotTo(HaveOccurred())
+	g.Expect(session.DisposeEventListener(eventListener)).NotTo(HaveOccurred())
+
+	persons := []*Person{
+		{Name: "Angela Scope", Born: 1970},
+		{Name: "James Thompson", Born: 1980},
+		{Name: "Jessica Thompson", Born: 1990},
+		{Name: "John Doe", Born: 2000}}
+	g.Expect(session.Save(&persons, saveOptions)).NotTo(HaveOccurred())
+	g.Expect(session.Clear()).NotTo(HaveOccurred())
+
+	lo := gogm.NewLoadOptions(dbName)
+	lo.Filters = []gogm.Filter{
+		{Property: "Name", Operator: gogm.STARTS_WITH, Value: "J"},
+		{Property: "born", Operator: gogm.GREATER_THAN_OR_EQUALS, Value: 1980}}
+	lo.OrderBy = []gogm.Order{{Property: "Born", Descending: true}}
+	lo.Skip = 1
+	lo.Limit = 2
+
+	var loaded []*Person
+	g.Expect(session.LoadAll(&loaded, nil, lo)).NotTo(HaveOccurred())
+	g.Expect(len(loaded)).To(Equal(2))
+	g.Expect(loaded[0].Name).To(Equal("Jessica Thompson"))
+	g.Expect(loaded[1].Name).To(Equal("James Thompson"))
+
+	loaded = nil
+	lo = gogm.NewLoadOptions(dbName)
+	lo.Filters = []gogm.Filter{{Property: "Name", Operator: gogm.IN, Value: []string{"Angela Scope", "John Doe"}}}
+	lo.OrderBy = []gogm.Order{{Property: "Name"}}
+	g.Expect(session.LoadAll(&loaded, nil, lo)).NotTo(HaveOccurred())
+	g.Expect(len(loaded)).To(Equal(2))
+	g.Expect(loaded[0].Name).To(Equal("Angela Scope"))
+	g.Expect(loaded[1].Name).To(Equal("John Doe"))
+
+	lo.Filters = []gogm.Filter{{Property: "unknown", Operator: gogm.IS_NULL}}
+	g.Expect(session.LoadAll(&loaded, nil, lo)).To(HaveOccurred(), "Only properties of the domain object can be filtered on")
+
+	g.Expect(session.PurgeDatabase(deleteOptions)).NotTo(HaveOccurred())
+	g.Expect(session.DisposeEventListener(eventListener)).NotTo(HaveOccurred())
+}
+
+func TestRepository(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(session.PurgeDatabase(deleteOptions)).NotTo(HaveOccurred())
+	g.Expect(session.DisposeEventListener(eventListener)).NotTo(HaveOccurred())
+
+	persons := gogm.NewRepository[Person](session)
+
+	angelaScope := &Person{Name: "Angela Scope"}
+	jamesThompson := &Person{Name: "James Thompson"}
+	g.Expect(persons.Save(angelaScope, saveOptions)).NotTo(HaveOccurred())
+	g.Expect(persons.SaveAll([]*Person{jamesThompson}, saveOptions)).NotTo(HaveOccurred())
+	g.Expect(angelaScope.ID).NotTo(BeNil())
+	g.Expect(jamesThompson.ID).NotTo(BeNil())
+
+	count, err := persons.Count(loadOptions)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(count).To(Equal(int64(2)))
+
+	g.Expect(session.Clear()).NotTo(HaveOccurred())
+	loaded, err := persons.FindByID(*angelaScope.ID, loadOptions)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(loaded.Name).To(Equal(angelaScope.Name))
+
+	all, err := persons.FindAll(nil, loadOptions)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(len(all)).To(Equal(2))
+
+	queried, err := persons.Query(loadOptions, "MATCH (person:Person) WHERE person.name = $name RETURN person", map[string]interface{}{"name": "James Thompson"})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(len(queried)).To(Equal(1))
+	g.Expect(queried[0].Name).To(Equal(jamesThompson.Name))
+
+	loadedID := *loaded.ID
+	g.Expect(persons.Delete(loaded, deleteOptions)).NotTo(HaveOccurred())
+	g.Expect(*loaded.ID).To(Equal(deletedID))
+	_, err = persons.FindByID(loadedID, loadOptions)
+	g.Expect(errors.Is(err, gogm.ErrNotFound)).To(BeTrue())
+	g.Expect(persons.DeleteAll(deleteOptions)).NotTo(HaveOccurred())
+
+	count, err = persons.Count(loadOptions)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(count).To(BeZero())
+
+	g.Expect(session.PurgeDatabase(deleteOptions)).NotTo(HaveOccurred())
+	g.Expect(session.DisposeEventListener(eventListener)).NotTo(HaveOccurred())
+}
+
+func TestSaveWithMerge(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(session.PurgeDatabase(deleteOptions)).NotTo(HaveOccurred())
+	g.Expect(session.DisposeEventListener(eventListener)).NotTo(HaveOccurred())
+
+	n9 := &Node9{}
+	n9.TestId = "n9"
+	n9.Name = "first"
+	g.Expect(session.Save(&n9, saveOptions)).NotTo(HaveOccurred())
+	g.Expect(session.Clear()).NotTo(HaveOccurred())
+
+	//The same entity received again from an external system
+	n9Again := &Node9{}
+	n9Again.TestId = "n9"
+	n9Again.Name = "second"
+	g.Expect(session.Save(&n9Again, saveOptions)).To(HaveOccurred(), "Creating the entity again violates the unique constraint on its custom ID")
+
+	mergeOptions := gogm.NewSaveOptions(dbName, math.MaxInt32/2)
+	mergeOptions.Merge = true
+	g.Expect(session.Save(&n9Again, mergeOptions)).NotTo(HaveOccurred())
+	g.Expect(*n9Again.ID).To(Equal(*n9.ID))
+
+	count, err := session.Count(loadOptions, "MATCH (n:Node9) RETURN COUNT(n)", nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(count).To(Equal(int64(1)))
+
+	var loaded *Node9
+	g.Expect(session.Clear()).NotTo(HaveOccurred())
+	g.Expect(session.Load(&loaded, "n9", loadOptions)).NotTo(HaveOccurred())
+	g.Expect(loaded.Name).To(Equal("second"))
+
+	g.Expect(session.PurgeDatabase(deleteOptions)).NotTo(HaveOccurred())
+	g.Expect(session.DisposeEventListener(eventListener)).NotTo(HaveOccurred())
+}
+
+func TestSaveInBatches(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(session.PurgeDatabase(deleteOptions)).NotTo(HaveOccurred())
+	g.Expect(session.RegisterEventListener(eventListener)).NotTo(HaveOccurred())
+
+	batchOptions := gogm.NewSaveOptions(dbName, math.MaxInt32/2)
+	batchOptions.BatchSize = 10
+
+	people := make([]*Person, 25)
+	for i := range people {
+		people[i] = &Person{Name: "person" + strconv.Itoa(i), Born: int64(1950 + i)}
+		if i > 0 {
+			people[i].Follows = []*Person{people[i-1]}
+		}
+	}
+	g.Expect(session.Save(&people, batchOptions)).NotTo(HaveOccurred())
+	for _, person := range people {
+		g.Expect(person.ID).NotTo(BeNil())
+		g.Expect(person.CreatedAt).NotTo(BeZero())
+	}
+
+	count, err := session.Count(loadOptions, "MATCH (n:Person) RETURN COUNT(n)", nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(count).To(Equal(int64(25)))
+	count, err = session.Count(loadOptions, "MATCH (:Person)-[r:FOLLOWS]->(:Person) RETURN COUNT(r)", nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(count).To(Equal(int64(24)))
+
+	//Only dirty entities are written
+	people[3].Born = 2000
+	people[4].Follows = nil
+	g.Expect(session.Save(&people, batchOptions)).NotTo(HaveOccurred())
+
+	var loaded *Person
+	g.Expect(session.Clear()).NotTo(HaveOccurred())
+	g.Expect(session.Load(&loaded, *people[4].ID, loadOptions)).NotTo(HaveOccurred())
+	g.Expect(loaded.Follows).To(BeEmpty())
+	g.Expect(session.Load(&loaded, *people[3].ID, loadOptions)).NotTo(HaveOccurred())
+	g.Expect(loaded.Born).To(Equal(int64(2000)))
+	g.Expect(loaded.Follows).To(HaveLen(1))
+	g.Expect(*loaded.Follows[0].ID).To(Equal(*people[2].ID))
+
+	batchOptions.BatchSize = -1
+	g.Expect(session.Save(&people, batchOptions)).To(HaveOccurred())
+
+	g.Expect(session.PurgeDatabase(deleteOptions)).NotTo(HaveOccurred())
+	g.Expect(session.DisposeEventListener(eventListener)).NotTo(HaveOccurred())
+}
+
+func TestOptimisticLocking(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(session.PurgeDatabase(deleteOptions)).NotTo(HaveOccurred())
+	g.Expect(session.RegisterEventListener(eventListener)).NotTo(HaveOccurred())
+
+	n11 := &Node11{Name: "created"}
+	g.Expect(session.Save(&n11, saveOptions)).NotTo(HaveOccurred())
+	g.Expect(n11.Version).To(Equal(int64(0)))
+
+	n11.Name = "updated"
+	g.Expect(session.Save(&n11, saveOptions)).NotTo(HaveOccurred())
+	g.Expect(n11.Version).To(Equal(int64(1)))
+
+	//Saving without changes doesn't increment the version
+	g.Expect(session.Save(&n11, saveOptions)).NotTo(HaveOccurred())
+	g.Expect(n11.Version).To(Equal(int64(1)))
+
+	//Another session updates the node
+	otherSession, err := ogm.NewSession(true)
+	g.Expect(err).NotTo(HaveOccurred())
+	var otherN11 *Node11
+	g.Expect(otherSession.Load(&otherN11, *n11.ID, loadOptions)).NotTo(HaveOccurred())
+	otherN11.Name = "updated by other session"
+	g.Expect(otherSession.Save(&otherN11, saveOptions)).NotTo(HaveOccurred())
+	g.Expect(otherN11.Version).To(Equal(int64(2)))
+
+	n11.Name = "stale update"
+	g.Expect(session.Save(&n11, saveOptions)).To(MatchError(gogm.ErrOptimisticLock))
+	g.Expect(n11.Version).To(Equal(int64(1)))
+
+	batchOptions := gogm.NewSaveOptions(dbName, math.MaxInt32/2)
+	batchOptions.BatchSize = 10
+	g.Expect(session.Save(&n11, batchOptions)).To(MatchError(gogm.ErrOptimisticLock))
+
+	//The stale update can be applied once the node is reloaded
+	g.Expect(session.Reload(loadOptions, &n11)).NotTo(HaveOccurred())
+	g.Expect(n11.Name).To(Equal("updated by other session"))
+	g.Expect(n11.Version).To(Equal(int64(2)))
+	n11.Name = "stale update"
+	g.Expect(session.Save(&n11, batchOptions)).NotTo(HaveOccurred())
+	g.Expect(n11.Version).To(Equal(int64(3)))
+
+	var loaded *Node11
+	g.Expect(otherSession.Reload(loadOptions, &otherN11)).NotTo(HaveOccurred())
+	g.Expect(otherSession.Load(&loaded, *n11.ID, loadOptions)).NotTo(HaveOccurred())
+	g.Expect(loaded.Name).To(Equal("stale update"))
+	g.Expect(loaded.Version).To(Equal(int64(3)))
+
+	//Rolling back a transaction restores the version, so that the object can be saved again
+	tx, err := session.BeginTransaction(dbName)
+	g.Expect(err).NotTo(HaveOccurred())
+	n11.Name = "rolled back"
+	g.Expect(session.Save(&n11, saveOptions)).NotTo(HaveOccurred())
+	g.Expect(n11.Version).To(Equal(int64(4)))
+	g.Expect(tx.RollBack()).NotTo(HaveOccurred())
+	g.Expect(tx.Close()).NotTo(HaveOccurred())
+	g.Expect(n11.Version).To(Equal(int64(3)))
+	g.Expect(session.Save(&n11, saveOptions)).NotTo(HaveOccurred())
+	g.Expect(n11.Version).To(Equal(int64(4)))
+
+	g.Expect(session.PurgeDatabase(deleteOptions)).NotTo(HaveOccurred())
+	g.Expect(session.DisposeEventListener(eventListener)).NotTo(HaveOccurred())
+}
+
+func TestAuditTimestamps(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(session.PurgeDatabase(deleteOptions)).NotTo(HaveOccurred())
+	g.Expect(session.RegisterEventListener(eventListener)).NotTo(HaveOccurred())
+
+	before := time.Now().Unix()
+	n12 := &Node12{Name: "created"}
+	g.Expect(session.Save(&n12, saveOptions)).NotTo(HaveOccurred())
+	g.Expect(n12.Created.Unix()).To(BeNumerically(">=", before))
+	g.Expect(n12.Modified).To(BeNumerically(">=", before))
+	created := n12.Created
+
+	var loaded *Node12
+	g.Expect(session.Clear()).NotTo(HaveOccurred())
+	g.Expect(session.Load(&loaded, *n12.ID, loadOptions)).NotTo(HaveOccurred())
+	g.Expect(loaded.Created.Equal(created)).To(BeTrue())
+	g.Expect(loaded.Modified).To(Equal(n12.Modified))
+
+	//Timestamps are only written when the object changed
+	loaded.Modified = 0
+	g.Expect(session.Save(&loaded, saveOptions)).NotTo(HaveOccurred())
+	g.Expect(loaded.Modified).To(BeNumerically(">=", before))
+	modified := loaded.Modified
+	g.Expect(session.Save(&loaded, saveOptions)).NotTo(HaveOccurred())
+	g.Expect(loaded.Modified).To(Equal(modified))
+
+	loaded.Name = "updated"
+	g.Expect(session.Save(&loaded, saveOptions)).NotTo(HaveOccurred())
+	g.Expect(loaded.Created.Equal(created)).To(BeTrue())
+
+	var reloaded *Node12
+	g.Expect(session.Clear()).NotTo(HaveOccurred())
+	g.Expect(session.Load(&reloaded, *n12.ID, loadOptions)).NotTo(HaveOccurred())
+	g.Expect(reloaded.Name).To(Equal("updated"))
+	g.Expect(reloaded.Created.Equal(created)).To(BeTrue())
+	g.Expect(reloaded.Modified).To(Equal(loaded.Modified))
+
+	//A failed save leaves the timestamps unchanged
+	_, err := session.Query(loadOptions, "MATCH (n:Node12) WHERE ID(n) = $id DETACH DELETE n", map[string]interface{}{"id": *n12.ID})
+	g.Expect(err).NotTo(HaveOccurred())
+	reloaded.Name = "deleted"
+	reloaded.Modified = 1
+	g.Expect(errors.Is(session.Save(&reloaded, saveOptions), gogm.ErrNotFound)).To(BeTrue())
+	g.Expect(reloaded.Modified).To(Equal(int64(1)))
+	g.Expect(reloaded.Created.Equal(created)).To(BeTrue())
+
+	g.Expect(session.PurgeDatabase(deleteOptions)).NotTo(HaveOccurred())
+	g.Expect(session.DisposeEventListener(eventListener)).NotTo(HaveOccurred())
+}
+
+func TestSoftDelete(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(session.PurgeDatabase(deleteOptions)).NotTo(HaveOccurred())
+	g.Expect(session.RegisterEventListener(eventListener)).NotTo(HaveOccurred())
+
+	kept := &Node13{Name: "kept"}
+	removed := &Node13{Name: "removed"}
+	nodes := []*Node13{kept, removed}
+	g.Expect(session.Save(&nodes, saveOptions)).NotTo(HaveOccurred())
+
+	g.Expect(session.Delete(&removed, deleteOptions)).NotTo(HaveOccurred())
+	g.Expect(removed.Removed).To(BeTrue())
+	g.Expect(removed.ID).NotTo(BeNil())
+	removedID := *removed.ID
+
+	count, err := session.Count(loadOptions, "MATCH (n:Node13) RETURN COUNT(n)", nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(count).To(Equal(int64(2)))
+
+	//Soft deleted entities aren't loaded by default
+	var loaded *Node13
+	g.Expect(session.Load(&loaded, removedID, loadOptions)).NotTo(HaveOccurred())
+	g.Expect(loaded).To(BeNil())
+
+	var loadedAll []*Node13
+	g.Expect(session.LoadAll(&loadedAll, nil, loadOptions)).NotTo(HaveOccurred())
+	g.Expect(loadedAll).To(HaveLen(1))
+	g.Expect(loadedAll[0].Name).To(Equal("kept"))
+
+	var ref *Node13
+	count, err = session.CountEntitiesOfType(loadOptions, &ref)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(count).To(Equal(int64(1)))
+
+	includeSoftDeleted := gogm.NewLoadOptions(dbName)
+	includeSoftDeleted.IncludeSoftDeleted = true
+	g.Expect(session.Clear()).NotTo(HaveOccurred())
+	g.Expect(session.Load(&loaded, removedID, includeSoftDeleted)).NotTo(HaveOccurred())
+	g.Expect(loaded).NotTo(BeNil())
+	g.Expect(loaded.Removed).To(BeTrue())
+	count, err = session.CountEntitiesOfType(includeSoftDeleted, &ref)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(count).To(Equal(int64(2)))
+
+	g.Expect(session.HardDelete(&loaded, deleteOptions)).NotTo(HaveOccurred())
+	g.Expect(*loaded.ID).To(Equal(int64(-1)))
+	count, err = session.CountEntitiesOfType(includeSoftDeleted, &ref)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(count).To(Equal(int64(1)))
+
+	g.Expect(session.PurgeDatabase(deleteOptions)).NotTo(HaveOccurred())
+	g.Expect(session.DisposeEventListener(eventListener)).NotTo(HaveOccurred())
+}
+
+func TestCascadeDelete(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(session.PurgeDatabase(deleteOptions)).NotTo(HaveOccurred())
+	g.Expect(session.RegisterEventListener(eventListener)).NotTo(HaveOccurred())
+
+	newAggregate := func() *Node14 {
+		n14 := &Node14{Name: "root"}
+		for _, name := range []string{"part0", "part1"} {
+			n14.Parts = append(n14.Parts, &Node15{Name: name, Details: []*Node16{{Name: name + "detail"}}})
+		}
+		n14.Related = []*Node16{{Name: "related"}}
+		g.Expect(session.Save(&n14, saveOptions)).NotTo(HaveOccurred())
+		return n14
+	}
+	countOf := func(label string) int64 {
+		count, err := session.Count(loadOptions, "MATCH (n:"+label+") RETURN COUNT(n)", nil)
+		g.Expect(err).NotTo(HaveOccurred())
+		return count
+	}
+
+	//The owned subgraph is deleted, but not the related nodes that aren't owned
+	n14 := newAggregate()
+	recorder := &RecordingEventListener{}
+	g.Expect(session.RegisterEventListener(recorder)).NotTo(HaveOccurred())
+	g.Expect(session.Delete(&n14, deleteOptions)).NotTo(HaveOccurred())
+	g.Expect(session.DisposeEventListener(recorder)).NotTo(HaveOccurred())
+	g.Expect(recorder.PreDeleted).To(ConsistOf("part0detail", "part1detail"))
+	g.Expect(recorder.Deleted).To(ConsistOf("part0detail", "part1detail"))
+	g.Expect(countOf("Node14")).To(Equal(int64(0)))
+	g.Expect(countOf("Node15")).To(Equal(int64(0)))
+	g.Expect(countOf("Node16")).To(Equal(int64(1)))
+	g.Expect(*n14.ID).To(Equal(int64(-1)))
+	for _, part := range n14.Parts {
+		g.Expect(*part.ID).To(Equal(int64(-1)))
+		g.Expect(*part.Details[0].ID).To(Equal(int64(-1)))
+	}
+	g.Expect(*n14.Related[0].ID).NotTo(Equal(int64(-1)))
+
+	var loaded *Node16
+	g.Expect(session.Load(&loaded, *n14.Related[0].ID, loadOptions)).NotTo(HaveOccurred())
+	g.Expect(loaded).NotTo(BeNil())
+
+	//Depth limits how far the delete cascades
+	g.Expect(session.PurgeDatabase(deleteOptions)).NotTo(HaveOccurred())
+	n14 = newAggregate()
+	depthOptions := gogm.NewDeleteOptions(dbName)
+	depthOptions.Depth = 1
+	g.Expect(session.Delete(&n14, depthOptions)).NotTo(HaveOccurred())
+	g.Expect(countOf("Node14")).To(Equal(int64(0)))
+	g.Expect(countOf("Node15")).To(Equal(int64(0)))
+	g.Expect(countOf("Node16")).To(Equal(int64(3)))
+
+	//Owned nodes that aren't loaded in the session are deleted too, with nil or zero options
+	for _, options := range []*gogm.DeleteOptions{nil, {}} {
+		g.Expect(session.PurgeDatabase(deleteOptions)).NotTo(HaveOccurred())
+		n14 = newAggregate()
+		g.Expect(session.Clear()).NotTo(HaveOccurred())
+		shallowLoadOptions := gogm.NewLoadOptions(dbName)
+		shallowLoadOptions.Depth = 0
+		var root *Node14
+		g.Expect(session.Load(&root, *n14.ID, shallowLoadOptions)).NotTo(HaveOccurred())
+		g.Expect(root.Parts).To(BeEmpty())
+		g.Expect(session.Delete(&root, options)).NotTo(HaveOccurred())
+		g.Expect(countOf("Node14")).To(Equal(int64(0)))
+		g.Expect(countOf("Node15")).To(Equal(int64(0)))
+		g.Expect(countOf("Node16")).To(Equal(int64(1)))
+	}
+
+	g.Expect(session.PurgeDatabase(deleteOptions)).NotTo(HaveOccurred())
+	g.Expect(session.DisposeEventListener(eventListener)).NotTo(HaveOccurred())
+}
+
+func TestTransact(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(session.PurgeDatabase(deleteOptions)).NotTo(HaveOccurred())
+	g.Expect(session.RegisterEventListener(eventListener)).NotTo(HaveOccurred())
+
+	countOf := func(name string) int64 {
+		count, err := session.Count(loadOptions, "MATCH (n:Node16) WHERE n.Name = $name RETURN COUNT(n)", map[string]interface{}{"name": name})
+		g.Expect(err).NotTo(HaveOccurred())
+		return count
+	}
+
+	//Commit when work returns nil
+	committed := &Node16{Name: "committed"}
+	g.Expect(session.Transact(dbName, func(tx gogm.Session) error {
+		g.Expect(tx.GetTransaction()).NotTo(BeNil())
+		return tx.Save(&committed, saveOptions)
+	})).NotTo(HaveOccurred())
+	g.Expect(session.GetTransaction()).To(BeNil())
+	g.Expect(committed.ID).NotTo(BeNil())
+	g.Expect(countOf("committed")).To(Equal(int64(1)))
+
+	//Roll back when work returns an error. The store and the IDs are restored
+	committedID := *committed.ID
+	rolledBack := &Node16{Name: "rolledBack"}
+	errWork := errors.New("work failed")
+	g.Expect(session.Transact(dbName, func(tx gogm.Session) error {
+		committed.Name = "updated"
+		g.Expect(tx.Save(&committed, saveOptions)).NotTo(HaveOccurred())
+		g.Expect(tx.Save(&rolledBack, saveOptions)).NotTo(HaveOccurred())
+		g.Expect(rolledBack.ID).NotTo(BeNil())
+		return errWork
+	})).To(Equal(errWork))
+	g.Expect(rolledBack.ID).To(BeNil())
+	g.Expect(*committed.ID).To(Equal(committedID))
+	g.Expect(countOf("rolledBack")).To(Equal(int64(0)))
+	g.Expect(countOf("updated")).To(Equal(int64(0)))
+
+	//The rolled back changes are saved again as the store holds the committed state
+	g.Expect(session.Save(&committed, saveOptions)).NotTo(HaveOccurred())
+	g.Expect(countOf("updated")).To(Equal(int64(1)))
+	g.Expect(session.Save(&rolledBack, saveOptions)).NotTo(HaveOccurred())
+	g.Expect(countOf("rolledBack")).To(Equal(int64(1)))
+
+	//Roll back and re-panic when work panics
+	panicked := &Node16{Name: "panicked"}
+	g.Expect(func() {
+		session.Transact(dbName, func(tx gogm.Session) error {
+			g.Expect(tx.Save(&panicked, saveOptions)).NotTo(HaveOccurred())
+			panic("work panicked")
+		})
+	}).To(PanicWith("work panicked"))
+	g.Expect(session.GetTransaction()).To(BeNil())
+	g.Expect(panicked.ID).To(BeNil())
+	g.Expect(countOf("panicked")).To(Equal(int64(0)))
+
+	//The transaction is ended by Transact only
+	managed := &Node16{Name: "managed"}
+	g.Expect(session.Transact(dbName, func(tx gogm.Session) error {
+		g.Expect(tx.Save(&managed, saveOptions)).NotTo(HaveOccurred())
+		g.Expect(errors.Is(tx.GetTransaction().Commit(), gogm.ErrTransactionState)).To(BeTrue())
+		g.Expect(errors.Is(tx.GetTransaction().RollBack(), gogm.ErrTransactionState)).To(BeTrue())
+		g.Expect(errors.Is(tx.GetTransaction().Close(), gogm.ErrTransactionState)).To(BeTrue())
+		return nil
+	})).NotTo(HaveOccurred())
+	g.Expect(countOf("managed")).To(Equal(int64(1)))
+
+	//Versions set by a rolled back Transact are restored
+	versioned := &Node11{Name: "versioned"}
+	g.Expect(session.Save(&versioned, saveOptions)).NotTo(HaveOccurred())
+	g.Expect(session.Transact(dbName, func(tx gogm.Session) error {
+		versioned.Name = "rolled back"
+		g.Expect(tx.Save(&versioned, saveOptions)).NotTo(HaveOccurred())
+		g.Expect(versioned.Version).To(Equal(int64(1)))
+		return errWork
+	})).To(Equal(errWork))
+	g.Expect(versioned.Version).To(Equal(int64(0)))
+	g.Expect(session.Save(&versioned, saveOptions)).NotTo(HaveOccurred())
+	g.Expect(versioned.Version).To(Equal(int64(1)))
+
+	//Transactions can't be nested
+	tx, err := session.BeginTransaction(dbName)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(session.Transact(dbName, func(tx gogm.Session) error { return nil })).To(HaveOccurred())
+	g.Expect(tx.RollBack()).NotTo(HaveOccurred())
+	g.Expect(tx.Close()).NotTo(HaveOccurred())
+
+	g.Expect(session.PurgeDatabase(deleteOptions)).NotTo(HaveOccurred())
+	g.Expect(session.DisposeEventListener(eventListener)).NotTo(HaveOccurred())
+}
+
+func TestDeferredEvents(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(session.PurgeDatabase(deleteOptions)).NotTo(HaveOccurred())
+	recorder := &RecordingEventListener{}
+	g.Expect(session.RegisterEventListener(recorder)).NotTo(HaveOccurred())
+
+	//Events are delivered once the transaction commits
+	committed := &Node16{Name: "committed"}
+	tx, err := session.BeginTransaction(dbName)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(session.Save(&committed, saveOptions)).NotTo(HaveOccurred())
+	g.Expect(recorder.Saved).To(BeEmpty())
+	g.Expect(tx.Commit()).NotTo(HaveOccurred())
+	g.Expect(tx.Close()).NotTo(HaveOccurred())
+	g.Expect(recorder.Saved).To(Equal([]string{"committed"}))
+
+	//Events are discarded when the transaction rolls back
+	rolledBack := &Node16{Name: "rolledBack"}
+	tx, err = session.BeginTransaction(dbName)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(session.Save(&rolledBack, saveOptions)).NotTo(HaveOccurred())
+	g.Expect(session.Delete(&committed, deleteOptions)).NotTo(HaveOccurred())
+	g.Expect(tx.RollBack()).NotTo(HaveOccurred())
+	g.Expect(tx.Close()).NotTo(HaveOccurred())
+	g.Expect(recorder.Saved).To(Equal([]string{"committed"}))
+	g.Expect(recorder.Deleted).To(BeEmpty())
+
+	//Events are delivered straight away outside of a transaction
+	g.Expect(session.Delete(&committed, deleteOptions)).NotTo(HaveOccurred())
+	g.Expect(recorder.Deleted).To(Equal([]string{"committed"}))
+
+	//Transact delivers the events of its committed attempt only
+	g.Expect(session.Transact(dbName, func(tx gogm.Session) error {
+		return tx.Save(&rolledBack, saveOptions)
+	})).NotTo(HaveOccurred())
+	g.Expect(session.Transact(dbName, func(tx gogm.Session) error {
+		g.Expect(tx.Delete(&rolledBack, deleteOptions)).NotTo(HaveOccurred())
+		return errors.New("work failed")
+	})).To(HaveOccurred())
+	g.Expect(recorder.Saved).To(Equal([]string{"committed", "rolledBack"}))
+	g.Expect(recorder.Deleted).To(Equal([]string{"committed"}))
+
+	g.Expect(session.PurgeDatabase(deleteOptions)).NotTo(HaveOccurred())
+	g.Expect(session.DisposeEventListener(recorder)).NotTo(HaveOccurred())
+}
+
+func TestFlush(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(session.PurgeDatabase(deleteOptions)).NotTo(HaveOccurred())
+	g.Expect(session.RegisterEventListener(eventListener)).NotTo(HaveOccurred())
+
+	countOf := func(cypher string) int64 {
+		count, err := session.Count(loadOptions, cypher, nil)
+		g.Expect(err).NotTo(HaveOccurred())
+		return count
+	}
+
+	n14 := &Node14{Name: "root", Parts: []*Node15{{Name: "part0"}}}
+	n16 := &Node16{Name: "unrelated"}
+	g.Expect(session.Save(&n14, saveOptions)).NotTo(HaveOccurred())
+	g.Expect(session.Save(&n16, saveOptions)).NotTo(HaveOccurred())
+
+	//Nothing to write
+	g.Expect(session.Flush(saveOptions)).NotTo(HaveOccurred())
+
+	//Property, relationship and new object changes of unrelated roots are written
+	n14.Name = "rootUpdate"
+	n14.Parts[0].Name = "part0Update"
+	n14.Parts = append(n14.Parts, &Node15{Name: "part1"})
+	n16.Name = "unrelatedUpdate"
+	g.Expect(session.Flush(saveOptions)).NotTo(HaveOccurred())
+	g.Expect(n14.Parts[1].ID).NotTo(BeNil())
+
+	g.Expect(countOf("MATCH (n:Node14 {Name: 'rootUpdate'})-[:PART]->(p:Node15) RETURN COUNT(p)")).To(Equal(int64(2)))
+	g.Expect(countOf("MATCH (p:Node15 {Name: 'part0Update'}) RETURN COUNT(p)")).To(Equal(int64(1)))
+	g.Expect(countOf("MATCH (n:Node16 {Name: 'unrelatedUpdate'}) RETURN COUNT(n)")).To(Equal(int64(1)))
+
+	//Removed relationships are deleted
+	n14.Parts = n14.Parts[:1]
+	g.Expect(session.Flush(saveOptions)).NotTo(HaveOccurred())
+	g.Expect(countOf("MATCH (:Node14)-[:PART]->(p:Node15) RETURN COUNT(p)")).To(Equal(int64(1)))
+
+	g.Expect(session.PurgeDatabase(deleteOptions)).NotTo(HaveOccurred())
+	g.Expect(session.DisposeEventListener(eventListener)).NotTo(HaveOccurred())
+}
+
+func TestChanges(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(session.PurgeDatabase(deleteOptions)).NotTo(HaveOccurred())
+	//The event listener updates objects after they're saved
+	g.Expect(session.DisposeEventListener(eventListener)).NotTo(HaveOccurred())
+
+	carol := &Person{Name: "Carol", Born: 1980}
+	dave := &Person{Name: "Dave"}
+	erin := &Person{Name: "Erin"}
+	carol.Follows = []*Person{dave}
+
+	//Objects not managed by the session are dirty
+	isDirty, err := session.IsDirty(&carol)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(isDirty).To(BeTrue())
+	changes, err := session.Changes(&carol)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(changes.Properties["Name"]).To(Equal(gogm.PropertyChange{Old: nil, New: "Carol"}))
+	g.Expect(changes.AddedLabels).To(Equal([]string{"Person"}))
+	g.Expect(changes.AddedRelationships).To(HaveLen(1))
+	g.Expect(carol.ID).To(BeNil(), "Computing changes doesn't assign IDs")
+
+	g.Expect(session.Save(&carol, saveOptions)).NotTo(HaveOccurred())
+	g.Expect(session.Save(&erin, saveOptions)).NotTo(HaveOccurred())
+	isDirty, err = session.IsDirty(&carol)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(isDirty).To(BeFalse())
+
+	//Properties, labels and relationships
+	carol.Born = 1981
+	carol.Tags = []string{"Author"}
+	carol.Follows = []*Person{erin}
+	isDirty, err = session.IsDirty(&carol)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(isDirty).To(BeTrue())
+	changes, err = session.Changes(&carol)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(changes.Properties).To(HaveLen(1))
+	g.Expect(changes.Properties["Born"]).To(Equal(gogm.PropertyChange{Old: int64(1980), New: int64(1981)}))
+	g.Expect(changes.AddedLabels).To(Equal([]string{"Author"}))
+	g.Expect(changes.RemovedLabels).To(BeEmpty())
+	g.Expect(changes.AddedRelationships).To(Equal([]gogm.RelationshipChange{{Type: "FOLLOWS", Node: erin}}))
+	g.Expect(changes.RemovedRelationships).To(Equal([]gogm.RelationshipChange{{Type: "FOLLOWS", Node: dave}}))
+
+	g.Expect(session.Save(&carol, saveOptions)).NotTo(HaveOccurred())
+	changes, err = session.Changes(&carol)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(changes.IsEmpty()).To(BeTrue())
+
+	g.Expect(session.PurgeDatabase(deleteOptions)).NotTo(HaveOccurred())
+	g.Expect(session.DisposeEventListener(eventListener)).NotTo(HaveOccurred())
+}
+
+func TestConcurrentSessions(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(session.PurgeDatabase(deleteOptions)).NotTo(HaveOccurred())
+	g.Expect(session.DisposeEventListener(eventListener)).NotTo(HaveOccurred())
+
+	//Goroutines share the OGM but have their own session. Run with -race
+	concurrentOgm := gogm.New(config)
+	const goroutines = 8
+	var (
+		wg     sync.WaitGroup
+		errs   = make(chan error, goroutines)
+		shared = &Node16{Name: "shared"}
+	)
+	g.Expect(session.Save(&shared, saveOptions)).NotTo(HaveOccurred())
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			goroutineSession, err := concurrentOgm.NewSession(true)
+			if err != nil {
+				errs <- err
+				return
+			}
+			n14 := &Node14{Name: "root" + strconv.Itoa(i), Parts: []*Node15{{Name: "part" + strconv.Itoa(i)}}}
+			if err = goroutineSession.Save(&n14, saveOptions); err != nil {
+				errs <- err
+				return
+			}
+			var loaded *Node14
+			if err = goroutineSession.Load(&loaded, *n14.ID, loadOptions); err != nil {
+				errs <- err
+				return
+			}
+			if loaded != n14 {
+				errs <- errors.New("a session loaded an object of another session")
+				return
+			}
+			//Event listeners are registered and disposed concurrently with the operations of a session
+			listener := &RecordingEventListener{}
+			if err = session.RegisterEventListener(listener); err != nil {
+				errs <- err
+				return
+			}
+			errs <- session.DisposeEventListener(listener)
+		}(i)
+	}
+	for i := 0; i < goroutines; i++ {
+		shared.Name = "shared" + strconv.Itoa(i)
+		g.Expect(session.Save(&shared, saveOptions)).NotTo(HaveOccurred())
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		g.Expect(err).NotTo(HaveOccurred())
+	}
+
+	count, err := session.Count(loadOptions, "MATCH (n:Node14)-[:PART]->(:Node15) RETURN COUNT(n)", nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(count).To(Equal(int64(goroutines)))
+
+	g.Expect(session.PurgeDatabase(deleteOptions)).NotTo(HaveOccurred())
+}
+
+func TestRegister(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(session.PurgeDatabase(deleteOptions)).NotTo(HaveOccurred())
+
+	registeringOgm := gogm.New(config)
+	g.Expect(registeringOgm.Register(&Movie{}, []*Person{}, Node16{}, &SimpleRelationship{})).NotTo(HaveOccurred())
+
+	//Mapping errors surface on registration
+	g.Expect(registeringOgm.Register(&struct{ Name string }{})).To(HaveOccurred())
+	g.Expect(registeringOgm.Register(nil)).To(HaveOccurred())
+
+	//Sessions use the registered metadata
+	registeredSession, err := registeringOgm.NewSession(true)
+	g.Expect(err).NotTo(HaveOccurred())
+	n16 := &Node16{Name: "registered"}
+	g.Expect(registeredSession.Save(&n16, saveOptions)).NotTo(HaveOccurred())
+	count, err := registeredSession.CountEntitiesOfType(loadOptions, &n16)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(count).To(Equal(int64(1)))
+
+	g.Expect(session.PurgeDatabase(deleteOptions)).NotTo(HaveOccurred())
+}
+
+func TestSchemaDialect(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(session.PurgeDatabase(deleteOptions)).NotTo(HaveOccurred())
+
+	//The dialect is detected from the server version
+	g.Expect(config.SchemaDialect).To(Equal(gogm.DetectSchemaDialect))
+	registeringOgm := gogm.New(config)
+	g.Expect(registeringOgm.Register(&Node17{})).NotTo(HaveOccurred())
+
+	count, err := session.Count(loadOptions, "SHOW INDEXES YIELD labelsOrTypes, properties WHERE labelsOrTypes = ['Node17'] AND properties = ['Title', 'Year'] RETURN COUNT(*)", nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(count).To(Equal(int64(1)))
+	count, err = session.Count(loadOptions, "SHOW CONSTRAINTS YIELD labelsOrTypes, properties WHERE labelsOrTypes = ['Node17'] AND properties = ['Code'] RETURN COUNT(*)", nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(count).To(Equal(int64(1)))
+
+	g.Expect(session.PurgeDatabase(deleteOptions)).NotTo(HaveOccurred())
+}
+
+func TestSchemaTags(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(session.PurgeDatabase(deleteOptions)).NotTo(HaveOccurred())
+
+	g.Expect(gogm.New(config).Register(&Node18{})).NotTo(HaveOccurred())
+	//Creating the schema is idempotent
+	g.Expect(gogm.New(config).Register(&Node18{})).NotTo(HaveOccurred())
+
+	names := []string{"index_Node18_byTitle", "index_Node18_byTitleYear", "text_Node18_Summary", "fulltext_Node18_search", "index_CITES_Page"}
+	count, err := session.Count(loadOptions, "SHOW INDEXES YIELD name WHERE name IN $names RETURN COUNT(*)", map[string]interface{}{"names": names})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(count).To(Equal(int64(len(names))))
+
+	count, err = session.Count(loadOptions, "SHOW INDEXES YIELD name, properties WHERE name = 'index_Node18_byTitleYear' AND properties = ['Title', 'Year'] RETURN COUNT(*)", nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(count).To(Equal(int64(1)))
+
+	count, err = session.Count(loadOptions, "SHOW CONSTRAINTS YIELD type, labelsOrTypes, properties WHERE type = 'NODE_KEY' AND labelsOrTypes = ['Node18'] AND properties = ['Isbn', 'Edition'] RETURN COUNT(*)", nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(count).To(Equal(int64(1)))
+
+	count, err = session.Count(loadOptions, "SHOW CONSTRAINTS YIELD type, labelsOrTypes, properties WHERE type = 'NODE_PROPERTY_EXISTENCE' AND labelsOrTypes = ['Node18'] AND properties = ['Author'] RETURN COUNT(*)", nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(count).To(Equal(int64(1)))
+
+	g.Expect(session.PurgeDatabase(deleteOptions)).NotTo(HaveOccurred())
+}
+
+func TestSchemaDiff(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(session.PurgeDatabase(deleteOptions)).NotTo(HaveOccurred())
+
+	//The database has the constraints and indexes of the other test domain objects too
+	node17 := func(items []gogm.SchemaItem) []gogm.SchemaItem {
+		var node17Items []gogm.SchemaItem
+		for _, item := range items {
+			if len(item.Labels) == 1 && item.Labels[0] == "Node17" {
+				node17Items = append(node17Items, item)
+			}
+		}
+		return node17Items
+	}
+
+	schemaOgm := gogm.New(config)
+	g.Expect(schemaOgm.Register(&Node17{})).NotTo(HaveOccurred())
+
+	report, err := schemaOgm.SchemaDiff("")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(report.Missing).To(BeEmpty())
+	g.Expect(report.Mismatched).To(BeEmpty())
+	g.Expect(node17(report.Extra)).To(BeEmpty())
+
+	//Drift: a stale index and a dropped constraint
+	_, err = session.Query(loadOptions, "CREATE INDEX stale_Node17_Code IF NOT EXISTS FOR (n:Node17) ON (n.Code, n.Year)", nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	names, err := session.Query(loadOptions, "SHOW CONSTRAINTS YIELD name, labelsOrTypes, properties WHERE labelsOrTypes = ['Node17'] AND properties = ['Code'] RETURN name", nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(len(names)).To(Equal(1))
+	_, err = session.Query(loadOptions, "DROP CONSTRAINT "+names[0]["name"].(string), nil)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	report, err = schemaOgm.SchemaDiff("")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(len(report.Missing)).To(Equal(1))
+	g.Expect(report.Missing[0].Kind).To(Equal(gogm.UniqueConstraint))
+	g.Expect(report.Missing[0].IsConstraint()).To(BeTrue())
+	g.Expect(report.Missing[0].Labels).To(Equal([]string{"Node17"}))
+	g.Expect(report.Missing[0].Properties).To(Equal([]string{"Code"}))
+	g.Expect(len(node17(report.Extra))).To(Equal(1))
+	g.Expect(node17(report.Extra)[0].Name).To(Equal("stale_Node17_Code"))
+	g.Expect(node17(report.Extra)[0].Kind).To(Equal(gogm.RangeIndex))
+	g.Expect(report.Mismatched).To(BeEmpty())
+
+	//Extra items are only dropped on demand
+	_, err = schemaOgm.SchemaApply("", nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	report, err = schemaOgm.SchemaDiff("")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(report.Missing).To(BeEmpty())
+	g.Expect(len(node17(report.Extra))).To(Equal(1))
+
+	_, err = schemaOgm.SchemaApply("", &gogm.SchemaApplyOptions{DropExtra: true})
+	g.Expect(err).NotTo(HaveOccurred())
+	report, err = schemaOgm.SchemaDiff("")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(report.IsEmpty()).To(BeTrue())
+
+	//Restores the constraints and indexes of the domain objects used by the other tests
+	_, err = ogm.SchemaApply("", nil)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(session.PurgeDatabase(deleteOptions)).NotTo(HaveOccurred())
+}
+
+func TestMigrations(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(session.PurgeDatabase(deleteOptions)).NotTo(HaveOccurred())
+
+	n := &Node17{Code: "m1", Title: "Migrated"}
+	g.Expect(session.Save(&n, nil)).NotTo(HaveOccurred())
+
+	fsys := fstest.MapFS{
+		"migrations/1_rename_title.up.cypher":   {Data: []byte("//Title becomes Name\nMATCH (n:Node17)\nSET n.Name = n.Title\nREMOVE n.Title;\n")},
+		"migrations/1_rename_title.down.cypher": {Data: []byte("MATCH (n:Node17)\nSET n.Title = n.Name\nREMOVE n.Name;\n")},
+	}
+	cypherMigrations, err := migrations.LoadCypher(fsys, "migrations")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(len(cypherMigrations)).To(Equal(1))
+	g.Expect(cypherMigrations[0].Version).To(Equal(int64(1)))
+	g.Expect(cypherMigrations[0].Description).To(Equal("rename title"))
+
+	countNamed := func() int64 {
+		count, err := session.Count(loadOptions, "MATCH (n:Node17 {Name: 'Migrated'}) RETURN COUNT(n)", nil)
+		g.Expect(err).NotTo(HaveOccurred())
+		return count
+	}
+
+	migrator := migrations.New(ogm, dbName)
+	g.Expect(migrator.Register(cypherMigrations...)).NotTo(HaveOccurred())
+	g.Expect(migrator.Register(cypherMigrations...)).To(HaveOccurred())
+
+	statuses, err := migrator.Status()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(len(statuses)).To(Equal(1))
+	g.Expect(statuses[0].Applied).To(BeFalse())
+
+	g.Expect(migrator.Up()).NotTo(HaveOccurred())
+	g.Expect(countNamed()).To(Equal(int64(1)))
+	statuses, err = migrator.Status()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(statuses[0].Applied).To(BeTrue())
+	g.Expect(statuses[0].AppliedAt.IsZero()).To(BeFalse())
+
+	//Applied migrations aren't applied again
+	g.Expect(migrator.Up()).NotTo(HaveOccurred())
+	count, err := session.Count(loadOptions, "MATCH (m:__GogmMigration) RETURN COUNT(m)", nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(count).To(Equal(int64(1)))
+
+	g.Expect(migrator.Down()).NotTo(HaveOccurred())
+	g.Expect(countNamed()).To(Equal(int64(0)))
+	statuses, err = migrator.Status()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(statuses[0].Applied).To(BeFalse())
+	g.Expect(migrator.Up()).NotTo(HaveOccurred())
+
+	//Go migrations
+	migrator = migrations.New(ogm, dbName)
+	migrator.LockTimeout = 0
+	g.Expect(migrator.Register(cypherMigrations...)).NotTo(HaveOccurred())
+	g.Expect(migrator.Register(migrations.Migration{
+		Version:     2,
+		Description: "backfill year",
+		Up: func(tx gogm.Session) error {
+			_, err := tx.Query(nil, "MATCH (n:Node17) SET n.Year = 2000", nil)
+			return err
+		}})).NotTo(HaveOccurred())
+
+	//A lock held by another migrator
+	_, err = session.Query(loadOptions, "CREATE (:__GogmMigrationLock {Name: 'migrations', Owner: 'other'})", nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(migrator.Up()).To(Equal(migrations.ErrLocked))
+	g.Expect(migrator.Unlock()).NotTo(HaveOccurred())
+
+	g.Expect(migrator.Up()).NotTo(HaveOccurred())
+	count, err = session.Count(loadOptions, "MATCH (n:Node17 {Name: 'Migrated', Year: 2000}) RETURN COUNT(n)", nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(count).To(Equal(int64(1)))
+	g.Expect(migrator.Down()).To(HaveOccurred())
+
+	//A failing migration is rolled back and the lock is released
+	g.Expect(migrator.Register(migrations.Migration{
+		Version: 3,
+		Up: func(tx gogm.Session) error {
+			if _, err := tx.Query(nil, "MATCH (n:Node17) SET n.Year = 3000", nil); err != nil {
+				return err
+			}
+			return errors.New("failed")
+		}})).NotTo(HaveOccurred())
+	g.Expect(migrator.Up()).To(HaveOccurred())
+	count, err = session.Count(loadOptions, "MATCH (n:Node17 {Year: 2000}) RETURN COUNT(n)", nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(count).To(Equal(int64(1)))
+	statuses, err = migrator.Status()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(len(statuses)).To(Equal(3))
+	g.Expect(statuses[2].Applied).To(BeFalse())
+	count, err = session.Count(loadOptions, "MATCH (l:__GogmMigrationLock) RETURN COUNT(l)", nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(count).To(Equal(int64(0)))
+
+	g.Expect(session.PurgeDatabase(deleteOptions)).NotTo(HaveOccurred())
+}
+
+func TestQueryHook(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(session.PurgeDatabase(deleteOptions)).NotTo(HaveOccurred())
+
+	queryHook := &RecordingQueryHook{}
+	logger := &RecordingLogger{}
+	tracedConfig := *config
+	tracedConfig.LogLevel = gogm.NONE
+	tracedConfig.QueryHook = queryHook
+	tracedConfig.Logger = logger
+	tracedConfig.RedactParameter = func(name string, value interface{}) interface{} {
+		if name == "Code" || name == "secret" {
+			return "***"
+		}
+		return value
+	}
+	tracedSession, err := gogm.New(&tracedConfig).NewSession(true)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	n := &Node17{Code: "traced", Title: "Traced"}
+	g.Expect(tracedSession.Save(&n, nil)).NotTo(HaveOccurred())
+
+	queryHook.Queries = nil
+	rows, err := tracedSession.Query(loadOptions, "MATCH (n:Node17) WHERE n.Code = $code RETURN n.Title AS title", map[string]interface{}{"code": "traced", "secret": "s3cr3t"})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(len(rows)).To(Equal(1))
+	g.Expect(len(queryHook.Queries)).To(Equal(1))
+	query := queryHook.Queries[0]
+	g.Expect(query.Cypher).To(Equal("MATCH (n:Node17) WHERE n.Code = $code RETURN n.Title AS title"))
+	g.Expect(query.DatabaseName).To(Equal(dbName))
+	g.Expect(query.Parameters).To(Equal(map[string]interface{}{"code": "traced", "secret": "***"}))
+	g.Expect(query.Records).To(Equal(1))
+	g.Expect(query.Duration).To(BeNumerically(">", 0))
+	g.Expect(query.Err).NotTo(HaveOccurred())
+	g.Expect(len(logger.Messages["debug"])).To(BeNumerically(">", 0))
+
+	//Statements of a save are traced too, with the properties of map parameters redacted
+	queryHook.Queries = nil
+	n.Code = "changed"
+	g.Expect(tracedSession.Save(&n, nil)).NotTo(HaveOccurred())
+	g.Expect(len(queryHook.Queries)).To(BeNumerically(">", 0))
+	var redacted bool
+	for _, query := range queryHook.Queries {
+		for _, value := range query.Parameters {
+			if properties, isMap := value.(map[string]interface{}); isMap && properties["Code"] == "***" {
+				redacted = true
+			}
+		}
+	}
+	g.Expect(redacted).To(BeTrue())
+
+	//Failed statements
+	queryHook.Queries = nil
+	_, err = tracedSession.Query(loadOptions, "RETURN invalid(", nil)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(len(queryHook.Queries)).To(Equal(1))
+	g.Expect(queryHook.Queries[0].Err).To(HaveOccurred())
+	g.Expect(len(logger.Messages["error"])).To(Equal(1))
+	g.Expect(queryHook.Before).To(BeNumerically(">=", 3))
+
+	g.Expect(session.PurgeDatabase(deleteOptions)).NotTo(HaveOccurred())
+}
+
+func TestWriteStats(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(session.PurgeDatabase(deleteOptions)).NotTo(HaveOccurred())
+
+	theMatrix := &Movie{}
+	theMatrix.Title = "The Matrix"
+	keanu := &Actor{}
+	keanu.Name = "Keanu Reeves"
+	character := &Character{Movie: theMatrix, Actor: keanu, Roles: []string{"Neo"}, Name: "Neo"}
+	theMatrix.Characters = append(theMatrix.Characters, character)
+	keanu.Characters = append(keanu.Characters, character)
+
+	stats := &gogm.WriteStats{}
+	statsSaveOptions := gogm.NewSaveOptions(dbName, math.MaxInt32/2)
+	statsSaveOptions.Stats = stats
+	g.Expect(session.Save(&theMatrix, statsSaveOptions)).NotTo(HaveOccurred())
+	g.Expect(stats.Statements).To(BeNumerically(">", 0))
+	g.Expect(stats.NodesCreated).To(Equal(2))
+	g.Expect(stats.RelationshipsCreated).To(Equal(1))
+	g.Expect(stats.PropertiesSet).To(BeNumerically(">", 0))
+
+	//Stats are reset by every operation
+	g.Expect(session.Save(&theMatrix, statsSaveOptions)).NotTo(HaveOccurred())
+	g.Expect(stats.NodesCreated).To(Equal(0))
+	theMatrix.Tagline = "Welcome to the Real World"
+	g.Expect(session.Save(&theMatrix, statsSaveOptions)).NotTo(HaveOccurred())
+	g.Expect(stats.NodesCreated).To(Equal(0))
+	g.Expect(stats.PropertiesSet).To(BeNumerically(">", 0))
+
+	statsDeleteOptions := gogm.NewDeleteOptions(dbName)
+	statsDeleteOptions.Stats = &gogm.WriteStats{}
+	g.Expect(session.Delete(&character, statsDeleteOptions)).NotTo(HaveOccurred())
+	g.Expect(statsDeleteOptions.Stats.RelationshipsDeleted).To(Equal(1))
+	g.Expect(statsDeleteOptions.Stats.NodesDeleted).To(Equal(0))
+
+	//Stats of a transaction
+	tx, err := session.BeginTransaction(dbName)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(session.Delete(&keanu, statsDeleteOptions)).NotTo(HaveOccurred())
+	g.Expect(tx.Commit()).NotTo(HaveOccurred())
+	g.Expect(tx.Close()).NotTo(HaveOccurred())
+	g.Expect(statsDeleteOptions.Stats.NodesDeleted).To(Equal(1))
+
+	g.Expect(session.PurgeDatabase(statsDeleteOptions)).NotTo(HaveOccurred())
+	g.Expect(statsDeleteOptions.Stats.NodesDeleted).To(Equal(1))
+	g.Expect(statsDeleteOptions.Stats.Statements).To(Equal(1))
+
+	g.Expect(session.PurgeDatabase(deleteOptions)).NotTo(HaveOccurred())
+}
+
+func TestDryRun(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(session.PurgeDatabase(deleteOptions)).NotTo(HaveOccurred())
+
+	theMatrix := &Movie{}
+	theMatrix.Title = "The Matrix"
+	keanu := &Actor{}
+	keanu.Name = "Keanu Reeves"
+	character := &Character{Movie: theMatrix, Actor: keanu, Roles: []string{"Neo"}, Name: "Neo"}
+	theMatrix.Characters = append(theMatrix.Characters, character)
+	keanu.Characters = append(keanu.Characters, character)
+
+	//Dry run save
+	for _, batchSize := range []int{0, 100} {
+		dryRunSaveOptions := gogm.NewSaveOptions(dbName, math.MaxInt32/2)
+		dryRunSaveOptions.BatchSize = batchSize
+		dryRunSaveOptions.DryRun = &gogm.DryRun{}
+		g.Expect(session.Save(&theMatrix, dryRunSaveOptions)).NotTo(HaveOccurred())
+		g.Expect(dryRunSaveOptions.DryRun.Statements).NotTo(BeEmpty())
+		g.Expect(dryRunSaveOptions.DryRun.Statements[0].DatabaseName).To(Equal(dbName))
+		g.Expect(dryRunSaveOptions.DryRun.Statements[0].Cypher).To(ContainSubstring("CREATE"))
+		g.Expect(theMatrix.ID).To(BeNil())
+		g.Expect(keanu.ID).To(BeNil())
+		g.Expect(character.ID).To(BeNil())
+
+		var movies []*Movie
+		g.Expect(session.LoadAll(&movies, nil, loadOptions)).NotTo(HaveOccurred())
+		g.Expect(movies).To(BeEmpty())
+	}
+
+	g.Expect(session.Save(&theMatrix, saveOptions)).NotTo(HaveOccurred())
+	g.Expect(theMatrix.ID).NotTo(BeNil())
+
+	//Dry run update of a versioned domain object
+	n11 := &Node11{Name: "created"}
+	g.Expect(session.Save(&n11, saveOptions)).NotTo(HaveOccurred())
+	n11.Name = "updated"
+	for _, batchSize := range []int{0, 100} {
+		dryRunSaveOptions := gogm.NewSaveOptions(dbName, 0)
+		dryRunSaveOptions.BatchSize = batchSize
+		dryRunSaveOptions.DryRun = &gogm.DryRun{}
+		g.Expect(session.Save(&n11, dryRunSaveOptions)).NotTo(HaveOccurred())
+		g.Expect(dryRunSaveOptions.DryRun.Statements).NotTo(BeEmpty())
+		g.Expect(n11.Version).To(Equal(int64(0)))
+	}
+	var loadedN11 *Node11
+	g.Expect(session.Load(&loadedN11, *n11.ID, gogm.NewLoadOptions(dbName))).NotTo(HaveOccurred())
+	g.Expect(loadedN11.Version).To(Equal(int64(0)))
+
+	//A dry run doesn't create the schema of a domain object used for the first time
+	names, err := session.Query(loadOptions, "SHOW CONSTRAINTS YIELD name, labelsOrTypes, properties WHERE labelsOrTypes = ['Node17'] AND properties = ['Code'] RETURN name", nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	for _, name := range names {
+		_, err = session.Query(loadOptions, "DROP CONSTRAINT "+name["name"].(string), nil)
+		g.Expect(err).NotTo(HaveOccurred())
+	}
+	schemaSession, err := gogm.New(config).NewSession(true)
+	g.Expect(err).NotTo(HaveOccurred())
+	n17 := &Node17{Code: "dry run"}
+	dryRunSaveOptions := gogm.NewSaveOptions(dbName, 0)
+	dryRunSaveOptions.DryRun = &gogm.DryRun{}
+	g.Expect(schemaSession.Save(&n17, dryRunSaveOptions)).NotTo(HaveOccurred())
+	constraintCount := "SHOW CONSTRAINTS YIELD labelsOrTypes, properties WHERE labelsOrTypes = ['Node17'] AND properties = ['Code'] RETURN COUNT(*)"
+	count, err := session.Count(loadOptions, constraintCount, nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(count).To(BeZero())
+	g.Expect(schemaSession.Save(&n17, saveOptions)).NotTo(HaveOccurred())
+	count, err = session.Count(loadOptions, constraintCount, nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(count).To(Equal(int64(1)))
+
+	//Dry run load
+	dryRunLoadOptions := gogm.NewLoadOptions(dbName)
+	dryRunLoadOptions.DryRun = &gogm.DryRun{}
+	var loadedMatrix *Movie
+	g.Expect(session.Load(&loadedMatrix, *theMatrix.ID, dryRunLoadOptions)).NotTo(HaveOccurred())
+	g.Expect(loadedMatrix).To(BeNil())
+	g.Expect(dryRunLoadOptions.DryRun.Statements).To(HaveLen(1))
+	g.Expect(dryRunLoadOptions.DryRun.Statements[0].Parameters).To(HaveKey("ids"))
+
+	//Dry run queries
+	dryRunLoadOptions = gogm.NewLoadOptions(dbName)
+	dryRunLoadOptions.DryRun = &gogm.DryRun{}
+	var movies []*Movie
+	g.Expect(session.QueryForObjects(dryRunLoadOptions, &movies, "MATCH (m:Movie) RETURN m", nil)).NotTo(HaveOccurred())
+	g.Expect(movies).To(BeEmpty())
+	rows, err := session.Query(dryRunLoadOptions, "MATCH (m:Movie) SET m.Title = 'The Matrix Reloaded' RETURN m", nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rows).To(BeEmpty())
+	count, err = session.Count(dryRunLoadOptions, "MATCH (m:Movie) RETURN COUNT(m)", nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(count).To(BeZero())
+	g.Expect(dryRunLoadOptions.DryRun.Statements).To(HaveLen(3))
+	g.Expect(dryRunLoadOptions.DryRun.Statements[2].Cypher).To(Equal("MATCH (m:Movie) RETURN COUNT(m)"))
+
+	//Dry run delete
+	dryRunDeleteOptions := gogm.NewDeleteOptions(dbName)
+	dryRunDeleteOptions.DryRun = &gogm.DryRun{}
+	g.Expect(session.Delete(&theMatrix, dryRunDeleteOptions)).NotTo(HaveOccurred())
+	g.Expect(dryRunDeleteOptions.DryRun.Statements).NotTo(BeEmpty())
+	g.Expect(theMatrix.ID).NotTo(BeNil())
+	g.Expect(session.PurgeDatabase(dryRunDeleteOptions)).NotTo(HaveOccurred())
+	g.Expect(dryRunDeleteOptions.DryRun.Statements).To(HaveLen(1))
+
+	g.Expect(session.Load(&loadedMatrix, *theMatrix.ID, loadOptions)).NotTo(HaveOccurred())
+	g.Expect(loadedMatrix).NotTo(BeNil())
+	g.Expect(loadedMatrix.Title).To(Equal("The Matrix"))
+
+	g.Expect(session.PurgeDatabase(deleteOptions)).NotTo(HaveOccurred())
+}
+
+func TestProfile(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(session.PurgeDatabase(deleteOptions)).NotTo(HaveOccurred())
+
+	theMatrix := &Movie{}
+	theMatrix.Title = "The Matrix"
+	keanu := &Actor{}
+	keanu.Name = "Keanu Reeves"
+	character := &Character{Movie: theMatrix, Actor: keanu, Roles: []string{"Neo"}, Name: "Neo"}
+	theMatrix.Characters = append(theMatrix.Characters, character)
+	keanu.Characters = append(keanu.Characters, character)
+
+	//Explained statements aren't run
+	explainSaveOptions := gogm.NewSaveOptions(dbName, math.MaxInt32/2)
+	explainSaveOptions.Profile = &gogm.Profile{Explain: true}
+	g.Expect(session.Save(&theMatrix, explainSaveOptions)).NotTo(HaveOccurred())
+	g.Expect(explainSaveOptions.Profile.Plans).NotTo(BeEmpty())
+	g.Expect(explainSaveOptions.Profile.Plans[0].Cypher).NotTo(HavePrefix("EXPLAIN"))
+	g.Expect(explainSaveOptions.Profile.Plans[0].Plan).NotTo(BeNil())
+	g.Expect(explainSaveOptions.Profile.Plans[0].Plan.Operator).NotTo(BeEmpty())
+	g.Expect(explainSaveOptions.Profile.Plans[0].Plan.DbHits).To(BeZero())
+	g.Expect(theMatrix.ID).To(BeNil())
+
+	var movies []*Movie
+	g.Expect(session.LoadAll(&movies, nil, loadOptions)).NotTo(HaveOccurred())
+	g.Expect(movies).To(BeEmpty())
+
+	//Profiled statements are run
+	profileSaveOptions := gogm.NewSaveOptions(dbName, math.MaxInt32/2)
+	profileSaveOptions.Profile = &gogm.Profile{}
+	g.Expect(session.Save(&theMatrix, profileSaveOptions)).NotTo(HaveOccurred())
+	g.Expect(theMatrix.ID).NotTo(BeNil())
+	g.Expect(profileSaveOptions.Profile.Plans).NotTo(BeEmpty())
+	g.Expect(profileSaveOptions.Profile.Plans[0].Plan.Rows).To(BeNumerically(">", 0))
+
+	profileLoadOptions := gogm.NewLoadOptions(dbName)
+	profileLoadOptions.Profile = &gogm.Profile{}
+	var loadedMatrix *Movie
+	g.Expect(session.Load(&loadedMatrix, *theMatrix.ID, profileLoadOptions)).NotTo(HaveOccurred())
+	g.Expect(loadedMatrix).NotTo(BeNil())
+	g.Expect(loadedMatrix.Title).To(Equal("The Matrix"))
+	g.Expect(profileLoadOptions.Profile.Plans).To(HaveLen(1))
+	g.Expect(profileLoadOptions.Profile.Plans[0].Plan.DbHits).To(BeNumerically(">", 0))
+	g.Expect(profileLoadOptions.Profile.Plans[0].Plan.Children).NotTo(BeEmpty())
+
+	explainLoadOptions := gogm.NewLoadOptions(dbName)
+	explainLoadOptions.Profile = &gogm.Profile{Explain: true}
+	movies = nil
+	g.Expect(session.LoadAll(&movies, nil, explainLoadOptions)).NotTo(HaveOccurred())
+	g.Expect(movies).To(BeEmpty())
+	g.Expect(explainLoadOptions.Profile.Plans).To(HaveLen(1))
+	g.Expect(explainLoadOptions.Profile.Plans[0].Plan.Operator).NotTo(BeEmpty())
+
+	g.Expect(session.PurgeDatabase(deleteOptions)).NotTo(HaveOccurred())
+}
+
+func TestErrors(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(session.PurgeDatabase(deleteOptions)).NotTo(HaveOccurred())
+
+	//ErrTransactionState
+	tx, err := session.BeginTransaction(dbName)
+	g.Expect(err).NotTo(HaveOccurred())
+	_, err = session.BeginTransaction(dbName)
+	g.Expect(errors.Is(err, gogm.ErrTransactionState)).To(BeTrue())
+	g.Expect(tx.Close()).NotTo(HaveOccurred())
+	g.Expect(errors.Is(tx.Close(), gogm.ErrTransactionState)).To(BeTrue())
+
+	//ErrConstraintViolation
+	first := &Node17{Code: "n17"}
+	g.Expect(session.Save(&first, saveOptions)).NotTo(HaveOccurred())
+	duplicate := &Node17{Code: "n17"}
+	err = session.Save(&duplicate, saveOptions)
+	g.Expect(errors.Is(err, gogm.ErrConstraintViolation)).To(BeTrue())
+	var neo4jError *neo4j.Neo4jError
+	g.Expect(errors.As(err, &neo4jError)).To(BeTrue())
+
+	//ErrMultipleResults
+	n16s := []*Node16{{Name: "n16"}, {Name: "n16"}}
+	g.Expect(session.Save(&n16s, saveOptions)).NotTo(HaveOccurred())
+	var n16 *Node16
+	err = session.QueryForObject(loadOptions, &n16, "MATCH (n:Node16) RETURN n", nil)
+	g.Expect(errors.Is(err, gogm.ErrMultipleResults)).To(BeTrue())
+
+	//ErrMapping
+	var n17 *Node17
+	err = session.QueryForObject(loadOptions, &n17, "MATCH (n:Node16) RETURN n LIMIT 1", nil)
+	var mappingError *gogm.ErrMapping
+	g.Expect(errors.As(err, &mappingError)).To(BeTrue())
+	g.Expect(mappingError.Type).To(Equal(reflect.TypeOf(n17)))
+
+	_, err = session.Query(loadOptions, "MATCH (n:Node16) WHERE ID(n) = $id SET n.name = 16", map[string]interface{}{"id": *n16s[0].ID})
+	g.Expect(err).NotTo(HaveOccurred())
+	err = session.Reload(loadOptions, &n16s[0])
+	g.Expect(errors.As(err, &mappingError)).To(BeTrue())
+	g.Expect(mappingError.Type).To(Equal(reflect.TypeOf(n16s[0])))
+	g.Expect(mappingError.Field).To(Equal("Name"))
+
+	_, err = session.Count(loadOptions, "RETURN 'many'", nil)
+	g.Expect(errors.As(err, &mappingError)).To(BeTrue())
+	g.Expect(mappingError.Type).To(BeNil())
+
+	//ErrNotFound
+	_, err = session.Query(loadOptions, "MATCH (n:Node16) WHERE ID(n) = $id DETACH DELETE n", map[string]interface{}{"id": *n16s[1].ID})
+	g.Expect(err).NotTo(HaveOccurred())
+	n16s[1].Name = "deleted"
+	g.Expect(errors.Is(session.Save(&n16s[1], saveOptions), gogm.ErrNotFound)).To(BeTrue())
+
+	g.Expect(session.PurgeDatabase(deleteOptions)).NotTo(HaveOccurred())
+}
@@ -77,6 +77,34 @@ func getIDAddr(g graph) **int64 {
 	return nil
 }
 
+//getTemporaryIDer sets the IDs of graphs like getIDer, but leaves the IDs of domain objects as is. Graphs of
+//domain objects without an ID get a temporary ID
+func getTemporaryIDer(store store) func(graph) {
+	var (
+		ID          func(g graph)
+		idGenerator = &internalIDGenerator{initialGraphID}
+	)
+	ID = func(g graph) {
+		if g.getValue().IsValid() {
+			if internalIDAddr := getIDAddr(g); *internalIDAddr != nil {
+				g.setID(**internalIDAddr)
+			} else {
+				g.setID(idGenerator.new())
+			}
+		} else {
+			for _, relatedGraph := range g.getRelatedGraphs() {
+				ID(relatedGraph)
+			}
+			if relationshipA := store.get(g); relationshipA != nil {
+				g.setID(relationshipA.getID())
+			} else {
+				g.setID(idGenerator.new())
+			}
+		}
+	}
+	return ID
+}
+
 func getIDer(idGenerator *internalIDGenerator, store store) func(graph) {
 	var ID func(g graph)
 	ID = func(g graph) {
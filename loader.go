@@ -23,9 +23,11 @@
 package gogm
 
 import (
+	"context"
 	"errors"
 	"reflect"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
@@ -35,17 +37,17 @@ import (
 type loader struct {
 	cypherExecuter *cypherExecuter
 	store          store
-	eventer        eventer
+	eventer        *eventer
 	registry       *registry
 	graphFactory   graphFactory
 	allowCyclicRef bool
 }
 
-func newLoader(cypherExecuter *cypherExecuter, store store, eventer eventer, registry *registry, graphFactory graphFactory, allowCyclicRef bool) *loader {
+func newLoader(cypherExecuter *cypherExecuter, store store, eventer *eventer, registry *registry, graphFactory graphFactory, allowCyclicRef bool) *loader {
 	return &loader{cypherExecuter, store, eventer, registry, graphFactory, allowCyclicRef}
 }
 
-func (l *loader) load(object interface{}, ID interface{}, loadOptions *LoadOptions, reload bool) (store, error) {
+func (l *loader) load(ctx context.Context, object interface{}, ID interface{}, loadOptions *LoadOptions, reload bool) (store, error) {
 
 	var (
 		valueOfObject = reflect.ValueOf(object)
@@ -74,14 +76,14 @@ func (l *loader) load(object interface{}, ID interface{}, loadOptions *LoadOptio
 
 	dummyValue := reflect.New(elem(reflect.TypeOf(object)).Elem())
 	graphs[0].setValue(&dummyValue)
-	sliceOfObjs, unloadedGraphs, err := l.loadAllOfGraphType(graphs[0], ptrToSliceIDs.Elem().Interface(), loadOptions, reload)
+	sliceOfObjs, unloadedGraphs, err := l.loadAllOfGraphType(ctx, graphs[0], ptrToSliceIDs.Elem().Interface(), loadOptions, reload)
 
-	if err != nil {
+	if err != nil || isDryRun(ctx) {
 		return nil, err
 	}
 
 	if sliceOfObjs.Len() > 1 {
-		return nil, errors.New("Got too many objects for ID " + valueOfID.String())
+		return nil, newKindError(ErrMultipleResults, "Got too many objects for ID "+valueOfID.String())
 	} else if sliceOfObjs.Len() == 1 {
 		valueOfObject.Elem().Set(sliceOfObjs.Index(0).Elem().Addr())
 	}
@@ -89,7 +91,7 @@ func (l *loader) load(object interface{}, ID interface{}, loadOptions *LoadOptio
 	return unloadedGraphs, err
 }
 
-func (l *loader) loadAll(objects interface{}, IDs interface{}, loadOptions *LoadOptions) error {
+func (l *loader) loadAll(ctx context.Context, objects interface{}, IDs interface{}, loadOptions *LoadOptions) error {
 
 	var (
 		graphs      []graph
@@ -112,7 +114,7 @@ func (l *loader) loadAll(objects interface{}, IDs interface{}, loadOptions *Load
 
 	dummyValue := reflect.New(elem(reflect.TypeOf(objects)).Elem())
 	graphs[0].setValue(&dummyValue)
-	if sliceOfObjs, _, err = l.loadAllOfGraphType(graphs[0], IDs, loadOptions, false); err != nil {
+	if sliceOfObjs, _, err = l.loadAllOfGraphType(ctx, graphs[0], IDs, loadOptions, false); err != nil || isDryRun(ctx) {
 		return err
 	}
 
@@ -122,7 +124,7 @@ func (l *loader) loadAll(objects interface{}, IDs interface{}, loadOptions *Load
 	return nil
 }
 
-func (l *loader) reload(lo *LoadOptions, objects ...interface{}) error {
+func (l *loader) reload(ctx context.Context, lo *LoadOptions, objects ...interface{}) error {
 	var err error
 	var graphs []graph
 	var IDer = getIDer(nil, nil)
@@ -130,10 +132,16 @@ func (l *loader) reload(lo *LoadOptions, objects ...interface{}) error {
 	var dbName string = ""
 
 	if lo != nil {
+		//Reloading syncs objects already in the session. Filters and pagination don't apply
+		reloadOptions := *lo
+		reloadOptions.Filters, reloadOptions.OrderBy, reloadOptions.Skip, reloadOptions.Limit = nil, nil, 0, 0
+		lo = &reloadOptions
 		dbName = lo.DatabaseName
 	} else {
 		lo = NewLoadOptions("")
 	}
+	//Soft deleted objects in the session are synced as well
+	lo.IncludeSoftDeleted = true
 
 	for _, object := range objects {
 		valueOfObject := reflect.ValueOf(object)
@@ -148,7 +156,10 @@ func (l *loader) reload(lo *LoadOptions, objects ...interface{}) error {
 		if storedGraph = l.store.get(graphs[0]); storedGraph == nil || !storedGraph.getValue().IsValid() {
 			continue
 		}
-		metadata, _ := l.registry.get(storedGraph.getValue().Type(), dbName)
+		metadata, err := l.registry.get(storedGraph.getValue().Type(), dbName)
+		if err != nil {
+			return err
+		}
 
 		ID := reflect.ValueOf(storedGraph.getID()).Interface()
 		customIDName, customIDValue := metadata.getCustomID(*storedGraph.getValue())
@@ -161,7 +172,7 @@ func (l *loader) reload(lo *LoadOptions, objects ...interface{}) error {
 		}
 		storedUnwound := unwind(storedGraph, lo.Depth, lo.DatabaseName)
 		var unloadedGraphs store
-		if unloadedGraphs, err = l.load(valueOfObject.Interface(), ID, lo, true); err != nil {
+		if unloadedGraphs, err = l.load(ctx, valueOfObject.Interface(), ID, lo, true); err != nil {
 			return err
 		}
 
@@ -183,7 +194,7 @@ func (l *loader) reload(lo *LoadOptions, objects ...interface{}) error {
 	return nil
 }
 
-func (l *loader) loadAllOfGraphType(refGraph graph, IDs interface{}, loadOptions *LoadOptions, reload bool) (reflect.Value, store, error) {
+func (l *loader) loadAllOfGraphType(ctx context.Context, refGraph graph, IDs interface{}, loadOptions *LoadOptions, reload bool) (reflect.Value, store, error) {
 
 	var (
 		typeOfRefGraph        = reflect.TypeOf(refGraph)
@@ -220,7 +231,7 @@ func (l *loader) loadAllOfGraphType(refGraph graph, IDs interface{}, loadOptions
 		IDsToLoad := reflect.New(sliceOfIDsToLoad.Type())
 		IDsToLoad.Elem().Set(sliceOfIDsToLoad)
 
-		if loadOptions.Depth <= -1 || reload {
+		if loadOptions.Depth <= -1 || reload || loadOptions.restrictsRoots() {
 			IDsToLoad.Elem().Set(valueOfIDs)
 		} else {
 			for i := 0; i < valueOfIDs.Len(); i++ {
@@ -236,7 +247,13 @@ func (l *loader) loadAllOfGraphType(refGraph graph, IDs interface{}, loadOptions
 					refGraph.setID(id)
 					storedGraph = l.store.get(refGraph)
 				}
-				if storedGraph != nil && storedGraph.getDepth() != nil && loadOptions.Depth*2 <= *storedGraph.getDepth() {
+				var softDeleted bool
+				if storedGraph != nil && !loadOptions.IncludeSoftDeleted {
+					if softDeleted, err = isSoftDeleted(storedGraph, l.registry, dbName); err != nil {
+						return invalidValue, nil, err
+					}
+				}
+				if storedGraph != nil && storedGraph.getDepth() != nil && loadOptions.Depth*2 <= *storedGraph.getDepth() && !softDeleted {
 					ptrToObjs.Elem().Set(reflect.Append(ptrToObjs.Elem(), *storedGraph.getValue()))
 				} else {
 					IDsToLoad.Elem().Set(reflect.Append(IDsToLoad.Elem(), ID))
@@ -256,10 +273,16 @@ func (l *loader) loadAllOfGraphType(refGraph graph, IDs interface{}, loadOptions
 		return invalidValue, nil, err
 	}
 
-	cql, params := cypherBuilder.getLoadAll(ids, loadOptions)
-	if records, err = l.cypherExecuter.collect(dbName, cql, params); err != nil {
+	cql, params, err := cypherBuilder.getLoadAll(ids, loadOptions)
+	if err != nil {
 		return invalidValue, nil, err
 	}
+	if records, err = l.cypherExecuter.collect(ctx, dbName, cql, params); err != nil {
+		return invalidValue, nil, err
+	}
+	if isDryRun(ctx) {
+		return sliceOfPtrToObjs, nil, nil
+	}
 
 	toUnLoad := newstore(nil)
 	visitedGraphs := newstore(nil)
@@ -268,9 +291,29 @@ func (l *loader) loadAllOfGraphType(refGraph graph, IDs interface{}, loadOptions
 	relatedValues[typeOfPrivateNode] = map[int64]map[int64]bool{}
 	relatedValues[typeOfPrivateRelationship] = map[int64]map[int64]bool{}
 
+	//Roots are appended in the order they are returned by the database to honor the load options' order
+	var rootIDs []int64
+	rootValues := map[int64]reflect.Value{}
 	for _, record := range records {
-		refGraph.setID(record.Values[1].(int64))
-		toUnLoad.save(l.getGraphToLoadFromDBResult(record.Values[0].(neo4j.Path), record.Values[2].([]interface{}), refGraph, visitedGraphs, loadOptions.Depth, dbName), dbName)
+		if len(record.Values) != 3 {
+			return invalidValue, nil, &ErrMapping{Type: refGraph.getValue().Type(), Reason: "unexpected columns in load result"}
+		}
+		path, isPath := record.Values[0].(neo4j.Path)
+		ID, isID := record.Values[1].(int64)
+		isDirectionInverted, isSlice := record.Values[2].([]interface{})
+		if !isPath || !isID || !isSlice || len(isDirectionInverted) != len(path.Relationships) {
+			return invalidValue, nil, &ErrMapping{Type: refGraph.getValue().Type(), Reason: "unexpected columns in load result"}
+		}
+		refGraph.setID(ID)
+		if _, isRoot := rootValues[refGraph.getID()]; !isRoot {
+			rootIDs = append(rootIDs, refGraph.getID())
+			rootValues[refGraph.getID()] = invalidValue
+		}
+		var graphToLoad graph
+		if graphToLoad, err = l.getGraphToLoadFromDBResult(path, isDirectionInverted, refGraph, visitedGraphs, loadOptions.Depth, dbName); err != nil {
+			return invalidValue, nil, err
+		}
+		toUnLoad.save(graphToLoad, dbName)
 	}
 
 	for _, g := range toUnLoad.all() {
@@ -292,25 +335,31 @@ func (l *loader) loadAllOfGraphType(refGraph graph, IDs interface{}, loadOptions
 		isRoot := toUnLoad.get(g) != nil
 		if stored := l.store.get(g); !reload && stored != nil && stored.getDepth() != nil && g.getDepth() != nil && *stored.getDepth() >= *g.getDepth() {
 			if stored.getValue().IsValid() {
-				for _, eventListener := range l.eventer.eventListeners {
+				for _, eventListener := range l.eventer.listeners() {
 					eventListener.OnPostLoad(event{object: stored.getValue()})
 				}
 			}
 			if isRoot {
-				ptrToObjs.Elem().Set(reflect.Append(ptrToObjs.Elem(), *stored.getValue()))
+				rootValues[g.getID()] = *stored.getValue()
 			}
 			continue
 		}
 
 		l.store.save(g, dbName)
 		if g.getValue().IsValid() {
-			for _, eventListener := range l.eventer.eventListeners {
+			for _, eventListener := range l.eventer.listeners() {
 				eventListener.OnPostLoad(event{object: g.getValue()})
 			}
 		}
 
 		if isRoot {
-			ptrToObjs.Elem().Set(reflect.Append(ptrToObjs.Elem(), *g.getValue()))
+			rootValues[g.getID()] = *g.getValue()
+		}
+	}
+
+	for _, rootID := range rootIDs {
+		if rootValues[rootID].IsValid() {
+			ptrToObjs.Elem().Set(reflect.Append(ptrToObjs.Elem(), rootValues[rootID]))
 		}
 	}
 
@@ -327,7 +376,7 @@ func getNodesMap(nodes []dbtype.Node) map[int64]dbtype.Node {
 	return tmpMap
 }
 
-func (l *loader) getGraphToLoadFromDBResult(path neo4j.Path, isDirectionInverted []interface{}, refGraph graph, visitedGraphs store, depth int, dbName string) graph {
+func (l *loader) getGraphToLoadFromDBResult(path neo4j.Path, isDirectionInverted []interface{}, refGraph graph, visitedGraphs store, depth int, dbName string) (graph, error) {
 
 	nodes := path.Nodes
 	relationships := path.Relationships
@@ -345,10 +394,10 @@ func (l *loader) getGraphToLoadFromDBResult(path neo4j.Path, isDirectionInverted
 		// from := nodes[index]
 		// to := nodes[index+1]
 		if from, rOk = nodesMap[neoRelationship.StartId]; !rOk {
-			panic("Could not find starting node of relationship")
+			return nil, &ErrMapping{Type: refGraph.getValue().Type(), Reason: "Could not find starting node of relationship"}
 		}
 		if to, rOk = nodesMap[neoRelationship.EndId]; !rOk {
-			panic("Could not find ending node of relationship")
+			return nil, &ErrMapping{Type: refGraph.getValue().Type(), Reason: "Could not find ending node of relationship"}
 		}
 
 		if visitedGraphs.relationship(neoRelationship.Id) == nil {
@@ -378,7 +427,7 @@ func (l *loader) getGraphToLoadFromDBResult(path neo4j.Path, isDirectionInverted
 			}
 
 			nodes := map[int64]graph{startNode: fromNode, endNode: toNode}
-			if isDirectionInverted[index].(bool) {
+			if isInverted, _ := isDirectionInverted[index].(bool); isInverted {
 				nodes = map[int64]graph{startNode: toNode, endNode: fromNode}
 			}
 
@@ -407,7 +456,7 @@ func (l *loader) getGraphToLoadFromDBResult(path neo4j.Path, isDirectionInverted
 		}
 	}
 
-	if graphToLoad == nil && len(relationships) == 0 {
+	if graphToLoad == nil && len(relationships) == 0 && len(nodes) > 0 {
 		node := &node{
 			properties:    nodes[0].Props,
 			label:         strings.Join(nodes[0].Labels, labelsDelim),
@@ -418,12 +467,16 @@ func (l *loader) getGraphToLoadFromDBResult(path neo4j.Path, isDirectionInverted
 		graphToLoad = node
 	}
 
+	if graphToLoad == nil {
+		return nil, &ErrMapping{Type: refGraph.getValue().Type(), Reason: "Could not find the object with id " + strconv.FormatInt(ID, 10) + " in its path"}
+	}
+
 	if graphToLoad.getValue() == nil {
 		v := reflect.New(graphToLoadType)
 		graphToLoad.setValue(&v)
 	}
 
-	return graphToLoad
+	return graphToLoad, nil
 }
 
 func (l *loader) unloadDBObject(g graph, unloadedGrahps store, depth int,
@@ -462,7 +515,9 @@ func (l *loader) unloadDBObject(g graph, unloadedGrahps store, depth int,
 		if unloadedGrahps.get(first) == nil {
 			if first.getValue().IsValid() {
 				driverPropertiesAsStructFieldValues(first.getProperties(), firstMetadata.getPropertyStructFields())
-				unloadGraphProperties(first, firstMetadata.getPropertyStructFields())
+				if err = unloadGraphProperties(first, firstMetadata.getPropertyStructFields()); err != nil {
+					return -1, err
+				}
 			}
 			unloadedGrahps.save(first, dbName)
 		}
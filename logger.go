@@ -0,0 +1,191 @@
+// MIT License
+//
+// Copyright (c) 2022 pmadhav
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package gogm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+)
+
+//Logger logs the activity of the OGM and of the driver. A *slog.Logger satisfies it, args being alternating
+//keys and values
+type Logger interface {
+	Debug(msg string, args ...interface{})
+	Info(msg string, args ...interface{})
+	Warn(msg string, args ...interface{})
+	Error(msg string, args ...interface{})
+}
+
+//Query is a statement run by the OGM, reported to a QueryHook
+type Query struct {
+	DatabaseName string
+	Cypher       string
+
+	//Parameters are redacted by Config.RedactParameter
+	Parameters map[string]interface{}
+
+	//Duration, Records and Err are set once the statement has run. Records is the number of records
+	//collected, 0 when the result isn't collected
+	Duration time.Duration
+	Records  int
+	Err      error
+}
+
+//QueryHook is called around every statement run by the OGM, e.g. to trace statements as spans
+type QueryHook interface {
+	//BeforeQuery is called before query runs. The context it returns, e.g. carrying a span, parents the statement
+	//and is passed to AfterQuery
+	BeforeQuery(ctx context.Context, query *Query) context.Context
+
+	//AfterQuery is called after query has run
+	AfterQuery(ctx context.Context, query *Query)
+}
+
+//tracer reports the statements run by the OGM to the logger and the query hook of the config
+type tracer struct {
+	logger          Logger
+	queryHook       QueryHook
+	redactParameter func(name string, value interface{}) interface{}
+}
+
+func newTracer(config *Config) *tracer {
+	return &tracer{
+		logger:          config.Logger,
+		queryHook:       config.QueryHook,
+		redactParameter: config.RedactParameter}
+}
+
+//trace runs a statement with run, reporting it to the logger and the query hook
+func (t *tracer) trace(ctx context.Context, dbName string, cql string, params map[string]interface{}, run func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	if t == nil || (t.logger == nil && t.queryHook == nil) {
+		return run(ctx)
+	}
+
+	query := &Query{
+		DatabaseName: dbName,
+		Cypher:       cql,
+		Parameters:   t.redact(params)}
+	hookCtx := ctx
+	if t.queryHook != nil {
+		if hookCtx = t.queryHook.BeforeQuery(ctx, query); hookCtx == nil {
+			hookCtx = ctx
+		}
+	}
+
+	start := time.Now()
+	result, err := run(hookCtx)
+	query.Duration = time.Since(start)
+	query.Records = getRecordCount(result)
+	query.Err = err
+
+	if t.queryHook != nil {
+		t.queryHook.AfterQuery(hookCtx, query)
+	}
+	if t.logger != nil {
+		args := []interface{}{"database", query.DatabaseName, "cypher", query.Cypher, "parameters", query.Parameters, "duration", query.Duration, "records", query.Records}
+		if err != nil {
+			t.logger.Error("cypher failed", append(args, "error", err)...)
+		} else {
+			t.logger.Debug("cypher", args...)
+		}
+	}
+	return result, err
+}
+
+//redact returns a copy of params whose values, including those of nested maps, are redacted
+func (t *tracer) redact(params map[string]interface{}) map[string]interface{} {
+	if params == nil || t.redactParameter == nil {
+		return params
+	}
+	redacted := make(map[string]interface{}, len(params))
+	for name, value := range params {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			redacted[name] = t.redact(v)
+		case []map[string]interface{}:
+			maps := make([]map[string]interface{}, len(v))
+			for i, m := range v {
+				maps[i] = t.redact(m)
+			}
+			redacted[name] = maps
+		case []interface{}:
+			values := make([]interface{}, len(v))
+			for i, element := range v {
+				if m, isMap := element.(map[string]interface{}); isMap {
+					values[i] = t.redact(m)
+				} else {
+					values[i] = t.redactParameter(name, element)
+				}
+			}
+			redacted[name] = values
+		default:
+			redacted[name] = t.redactParameter(name, value)
+		}
+	}
+	return redacted
+}
+
+func getRecordCount(result interface{}) int {
+	switch records := result.(type) {
+	case []*neo4j.Record:
+		return len(records)
+	case *neo4j.Record:
+		if records != nil {
+			return 1
+		}
+	}
+	return 0
+}
+
+//driverLogger routes the logs of the driver up to level to a Logger
+type driverLogger struct {
+	logger Logger
+	level  LogLevel
+}
+
+func (l *driverLogger) Error(name string, id string, err error) {
+	if l.level >= ERROR {
+		l.logger.Error(err.Error(), "component", name, "id", id)
+	}
+}
+
+func (l *driverLogger) Warnf(name string, id string, msg string, args ...interface{}) {
+	if l.level >= WARNING {
+		l.logger.Warn(fmt.Sprintf(msg, args...), "component", name, "id", id)
+	}
+}
+
+func (l *driverLogger) Infof(name string, id string, msg string, args ...interface{}) {
+	if l.level >= INFO {
+		l.logger.Info(fmt.Sprintf(msg, args...), "component", name, "id", id)
+	}
+}
+
+func (l *driverLogger) Debugf(name string, id string, msg string, args ...interface{}) {
+	if l.level >= DEBUG {
+		l.logger.Debug(fmt.Sprintf(msg, args...), "component", name, "id", id)
+	}
+}
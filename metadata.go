@@ -27,32 +27,69 @@ import (
 	"reflect"
 	"sort"
 	"strings"
+	"sync"
 )
 
 type metadata interface {
 	getLabel(reflect.Value) (string, error)
 	getProperties(reflect.Value) map[string]interface{}
 	getCustomID(reflect.Value) (string, reflect.Value)
+	getVersion(reflect.Value) (string, reflect.Value)
+	getCreatedAt(reflect.Value) (string, reflect.Value)
+	getUpdatedAt(reflect.Value) (string, reflect.Value)
+	getSoftDeleteBackendName() string
 	loadRelatedGraphs(g graph, ID func(graph), registry *registry, dbName string) (map[int64]graph, error)
 	getGraphField(ref graph, relatedGraph graph) (*field, error)
 	getPropertyStructFields() map[string]*reflect.StructField
 	getStructLabel() string
 	getType() reflect.Type
+	initSchema(dbName string, create func() error) error
+	isSchemaCreated(dbName string) bool
 }
 
 type commonMetadata struct {
-	name                 string
-	structLabel          string
-	registry             *registry
-	propertyStructFields map[string]*reflect.StructField
-	customIDBackendName  string
-	_type                reflect.Type
+	name                  string
+	structLabel           string
+	registry              *registry
+	propertyStructFields  map[string]*reflect.StructField
+	customIDBackendName   string
+	versionBackendName    string
+	createdAtBackendName  string
+	updatedAtBackendName  string
+	softDeleteBackendName string
+	_type                 reflect.Type
+
+	schemaMu        sync.Mutex
+	schemaDatabases map[string]bool //databases the schema was created in
 }
 
 func (c *commonMetadata) getType() reflect.Type {
 	return c._type
 }
 
+//initSchema calls create the first time the schema is needed in dbName, until create succeeds
+func (c *commonMetadata) initSchema(dbName string, create func() error) error {
+	c.schemaMu.Lock()
+	defer c.schemaMu.Unlock()
+	if c.schemaDatabases[dbName] {
+		return nil
+	}
+	if err := create(); err != nil {
+		return err
+	}
+	if c.schemaDatabases == nil {
+		c.schemaDatabases = map[string]bool{}
+	}
+	c.schemaDatabases[dbName] = true
+	return nil
+}
+
+func (c *commonMetadata) isSchemaCreated(dbName string) bool {
+	c.schemaMu.Lock()
+	defer c.schemaMu.Unlock()
+	return c.schemaDatabases[dbName]
+}
+
 func (c *commonMetadata) getStructLabel() string {
 	return c.structLabel
 }
@@ -64,6 +101,31 @@ func (c *commonMetadata) getCustomID(v reflect.Value) (string, reflect.Value) {
 	return emptyString, invalidValue
 }
 
+func (c *commonMetadata) getVersion(v reflect.Value) (string, reflect.Value) {
+	if c.versionBackendName != emptyString {
+		return c.versionBackendName, v.Elem().FieldByName(c.propertyStructFields[c.versionBackendName].Name)
+	}
+	return emptyString, invalidValue
+}
+
+func (c *commonMetadata) getCreatedAt(v reflect.Value) (string, reflect.Value) {
+	if c.createdAtBackendName != emptyString {
+		return c.createdAtBackendName, v.Elem().FieldByName(c.propertyStructFields[c.createdAtBackendName].Name)
+	}
+	return emptyString, invalidValue
+}
+
+func (c *commonMetadata) getUpdatedAt(v reflect.Value) (string, reflect.Value) {
+	if c.updatedAtBackendName != emptyString {
+		return c.updatedAtBackendName, v.Elem().FieldByName(c.propertyStructFields[c.updatedAtBackendName].Name)
+	}
+	return emptyString, invalidValue
+}
+
+func (c *commonMetadata) getSoftDeleteBackendName() string {
+	return c.softDeleteBackendName
+}
+
 func (c *commonMetadata) getPropertyStructFields() map[string]*reflect.StructField {
 	return c.propertyStructFields
 }
@@ -114,6 +176,21 @@ func getMetadata(t reflect.Type, registry *registry, dbName string) (metadata, e
 	if customIDBackendName, err = getCustomIDBackendName(propertyStructFields); err != nil {
 		return nil, err
 	}
+	var versionBackendName string
+	if versionBackendName, err = getVersionBackendName(propertyStructFields); err != nil {
+		return nil, err
+	}
+	var createdAtBackendName, updatedAtBackendName string
+	if createdAtBackendName, err = getTimestampBackendName(propertyStructFields, createdAtTag); err != nil {
+		return nil, err
+	}
+	if updatedAtBackendName, err = getTimestampBackendName(propertyStructFields, updatedAtTag); err != nil {
+		return nil, err
+	}
+	var softDeleteBackendName string
+	if softDeleteBackendName, err = getSoftDeleteBackendName(propertyStructFields); err != nil {
+		return nil, err
+	}
 
 	if typeOfInternalGraph == typeOfPrivateRelationship {
 		r := newRelationshipMetadata()
@@ -122,6 +199,10 @@ func getMetadata(t reflect.Type, registry *registry, dbName string) (metadata, e
 		r.structLabel = getRelationshipType(typeOfObject.Elem())
 		r.propertyStructFields = propertyStructFields
 		r.customIDBackendName = customIDBackendName
+		r.versionBackendName = versionBackendName
+		r.createdAtBackendName = createdAtBackendName
+		r.updatedAtBackendName = updatedAtBackendName
+		r.softDeleteBackendName = softDeleteBackendName
 		r._type = typeOfObject
 
 		endpointFields, _ := getFeilds(valueOfObject.Elem(), isRelationshipEndPointFieldFilter(startNodeTag), isRelationshipEndPointFieldFilter(endNodeTag))
@@ -150,6 +231,10 @@ func getMetadata(t reflect.Type, registry *registry, dbName string) (metadata, e
 		n.registry = registry
 		n.name = typeOfObject.String()
 		n.customIDBackendName = customIDBackendName
+		n.versionBackendName = versionBackendName
+		n.createdAtBackendName = createdAtBackendName
+		n.updatedAtBackendName = updatedAtBackendName
+		n.softDeleteBackendName = softDeleteBackendName
 		n.thisStructLabel = getThisStructLabels(typeOfObject.Elem())
 		n._type = typeOfObject
 
@@ -0,0 +1,124 @@
+// MIT License
+//
+// Copyright (c) 2022 pmadhav
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package migrations
+
+import (
+	"errors"
+	"io/fs"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	gogm "github.com/pmadhav/neo4j-go-ogm"
+)
+
+//cypherFileName matches <version>_<description>.up.cypher and <version>_<description>.down.cypher
+var cypherFileName = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.cypher$`)
+
+//LoadCypher loads the migrations of the .cypher files of dir in fsys, e.g. an embed.FS. Files are named
+//<version>_<description>.up.cypher and, to revert them, <version>_<description>.down.cypher. Underscores of the
+//description are read as spaces. Statements are terminated by a semicolon at the end of a line and lines starting
+//with // are comments
+func LoadCypher(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		migrations []Migration
+		byVersion  = map[int64]*Migration{}
+		versions   []int64
+	)
+	for _, entry := range entries {
+		matches := cypherFileName.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || matches == nil {
+			continue
+		}
+		version, err := strconv.ParseInt(matches[1], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration := byVersion[version]
+		if migration == nil {
+			migration = &Migration{Version: version, Description: strings.ReplaceAll(matches[2], "_", " ")}
+			byVersion[version] = migration
+			versions = append(versions, version)
+		}
+		if matches[3] == "up" {
+			migration.Up = runStatements(getStatements(string(content)))
+		} else {
+			migration.Down = runStatements(getStatements(string(content)))
+		}
+	}
+
+	for _, version := range versions {
+		if byVersion[version].Up == nil {
+			return nil, errors.New("migration " + strconv.FormatInt(version, 10) + " has a down file but no up file")
+		}
+		migrations = append(migrations, *byVersion[version])
+	}
+	return migrations, nil
+}
+
+//getStatements splits cypher into its statements
+func getStatements(cypher string) []string {
+	var (
+		statements []string
+		statement  []string
+	)
+	for _, line := range strings.Split(cypher, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "//") {
+			continue
+		}
+		if strings.HasSuffix(trimmed, ";") {
+			statement = append(statement, strings.TrimSuffix(trimmed, ";"))
+			statements = append(statements, strings.Join(statement, "\n"))
+			statement = nil
+			continue
+		}
+		statement = append(statement, trimmed)
+	}
+	if len(statement) > 0 {
+		statements = append(statements, strings.Join(statement, "\n"))
+	}
+	return statements
+}
+
+func runStatements(statements []string) func(tx gogm.Session) error {
+	return func(tx gogm.Session) error {
+		for _, statement := range statements {
+			if _, err := tx.Query(nil, statement, nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
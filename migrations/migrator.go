@@ -0,0 +1,317 @@
+// MIT License
+//
+// Copyright (c) 2022 pmadhav
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//Package migrations applies versioned data migrations, e.g. renaming a property, relabeling nodes or backfilling
+//custom IDs, with the sessions of an OGM instance. Applied versions are recorded as __GogmMigration nodes in each
+//database and a __GogmMigrationLock node guards against concurrent migrators
+package migrations
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	gogm "github.com/pmadhav/neo4j-go-ogm"
+)
+
+const (
+	lockName         = "migrations"
+	lockPollInterval = time.Second
+
+	//DefaultLockTimeout is how long a migrator waits for the lock by default
+	DefaultLockTimeout = time.Minute
+)
+
+//ErrLocked is returned by Up and Down when another migrator holds the lock of the database past the lock timeout.
+//A lock left over by a crashed migrator is released with Unlock
+var ErrLocked = errors.New("migrations are locked by another migrator")
+
+//Migration is a versioned change of the data of a database. Up and Down run in a transaction function which
+//also records the migration, hence they may run more than once when the transaction is retried
+type Migration struct {
+	Version     int64
+	Description string
+	Up          func(tx gogm.Session) error
+
+	//Down reverts Up. A migration without Down can't be reverted
+	Down func(tx gogm.Session) error
+}
+
+//Status is the state of a migration in a database
+type Status struct {
+	Version     int64
+	Description string
+	Applied     bool
+
+	//AppliedAt is zero for a pending migration
+	AppliedAt time.Time
+}
+
+//migrationRecord records an applied migration
+type migrationRecord struct {
+	gogm.Node   `gogm:"label:__GogmMigration"`
+	Version     int64 `gogm:"unique"`
+	Description string
+	AppliedAt   time.Time `gogm:"createdAt"`
+}
+
+//migrationLock is held by the migrator applying or reverting migrations. Its uniqueness constraint makes
+//concurrent migrators merge the same lock
+type migrationLock struct {
+	gogm.Node  `gogm:"label:__GogmMigrationLock"`
+	Name       string `gogm:"unique"`
+	Owner      string
+	AcquiredAt time.Time
+}
+
+//Migrator applies and reverts the migrations registered with it in a database
+type Migrator struct {
+	//LockTimeout is how long Up and Down wait for a concurrent migrator to release the lock
+	LockTimeout time.Duration
+
+	ogm        *gogm.Gogm
+	dbName     string
+	owner      string
+	migrations []Migration
+}
+
+//New creates a migrator of the database dbName, the default database when empty
+func New(ogm *gogm.Gogm, dbName string) *Migrator {
+	return &Migrator{
+		LockTimeout: DefaultLockTimeout,
+		ogm:         ogm,
+		dbName:      dbName,
+		owner:       newOwner()}
+}
+
+//Register registers migrations. Migrations are applied in the order of their versions, which must be unique
+func (m *Migrator) Register(migrations ...Migration) error {
+	for _, migration := range migrations {
+		if migration.Up == nil {
+			return errors.New(fmt.Sprint("migration ", migration.Version, " has no Up"))
+		}
+		for _, registered := range m.migrations {
+			if registered.Version == migration.Version {
+				return errors.New(fmt.Sprint("duplicate migration version ", migration.Version))
+			}
+		}
+		m.migrations = append(m.migrations, migration)
+	}
+	sort.Slice(m.migrations, func(i, j int) bool {
+		return m.migrations[i].Version < m.migrations[j].Version
+	})
+	return nil
+}
+
+//Up applies the pending migrations in the order of their versions. Each migration is applied and recorded in
+//its own transaction, so that a failing migration leaves the migrations before it applied
+func (m *Migrator) Up() error {
+	return m.UpCtx(context.Background())
+}
+
+func (m *Migrator) UpCtx(ctx context.Context) error {
+	return m.locked(ctx, func(session gogm.Session) error {
+		applied, err := m.getApplied(ctx, session)
+		if err != nil {
+			return err
+		}
+		for _, migration := range m.migrations {
+			if applied[migration.Version] != nil {
+				continue
+			}
+			migration := migration
+			if err = session.TransactCtx(ctx, m.dbName, func(tx gogm.Session) error {
+				if err := migration.Up(tx); err != nil {
+					return err
+				}
+				record := &migrationRecord{Version: migration.Version, Description: migration.Description}
+				return tx.SaveCtx(ctx, &record, gogm.NewSaveOptions(m.dbName, 0))
+			}); err != nil {
+				return fmt.Errorf("migration %d failed: %w", migration.Version, err)
+			}
+		}
+		return nil
+	})
+}
+
+//Down reverts the applied migration of the latest version
+func (m *Migrator) Down() error {
+	return m.DownCtx(context.Background())
+}
+
+func (m *Migrator) DownCtx(ctx context.Context) error {
+	return m.locked(ctx, func(session gogm.Session) error {
+		applied, err := m.getApplied(ctx, session)
+		if err != nil {
+			return err
+		}
+		var latest *migrationRecord
+		for _, record := range applied {
+			if latest == nil || record.Version > latest.Version {
+				latest = record
+			}
+		}
+		if latest == nil {
+			return nil
+		}
+
+		var migration *Migration
+		for i := range m.migrations {
+			if m.migrations[i].Version == latest.Version {
+				migration = &m.migrations[i]
+			}
+		}
+		if migration == nil {
+			return errors.New(fmt.Sprint("applied migration ", latest.Version, " isn't registered"))
+		}
+		if migration.Down == nil {
+			return errors.New(fmt.Sprint("migration ", latest.Version, " can't be reverted: it has no Down"))
+		}
+		if err = session.TransactCtx(ctx, m.dbName, func(tx gogm.Session) error {
+			if err := migration.Down(tx); err != nil {
+				return err
+			}
+			_, err := tx.QueryCtx(ctx, nil, `MATCH (m:__GogmMigration {Version: $version}) DELETE m`, map[string]interface{}{"version": migration.Version})
+			return err
+		}); err != nil {
+			return fmt.Errorf("migration %d failed: %w", migration.Version, err)
+		}
+		return nil
+	})
+}
+
+//Status returns the state of the registered migrations and of the applied migrations which aren't registered,
+//in the order of their versions
+func (m *Migrator) Status() ([]Status, error) {
+	return m.StatusCtx(context.Background())
+}
+
+func (m *Migrator) StatusCtx(ctx context.Context) ([]Status, error) {
+	session, err := m.ogm.NewSession(false)
+	if err != nil {
+		return nil, err
+	}
+	applied, err := m.getApplied(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []Status
+	for _, migration := range m.migrations {
+		status := Status{Version: migration.Version, Description: migration.Description}
+		if record := applied[migration.Version]; record != nil {
+			status.Applied = true
+			status.AppliedAt = record.AppliedAt
+			delete(applied, migration.Version)
+		}
+		statuses = append(statuses, status)
+	}
+	for _, record := range applied {
+		statuses = append(statuses, Status{Version: record.Version, Description: record.Description, Applied: true, AppliedAt: record.AppliedAt})
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+	return statuses, nil
+}
+
+//Unlock releases the lock of the database whoever holds it. Use it to recover from a migrator which crashed
+//while holding the lock
+func (m *Migrator) Unlock() error {
+	return m.UnlockCtx(context.Background())
+}
+
+func (m *Migrator) UnlockCtx(ctx context.Context) error {
+	session, err := m.ogm.NewSession(true)
+	if err != nil {
+		return err
+	}
+	_, err = session.QueryCtx(ctx, gogm.NewLoadOptions(m.dbName), `MATCH (l:__GogmMigrationLock {Name: $name}) DELETE l`, map[string]interface{}{"name": lockName})
+	return err
+}
+
+//locked runs work while holding the lock of the database
+func (m *Migrator) locked(ctx context.Context, work func(session gogm.Session) error) error {
+	session, err := m.ogm.NewSession(true)
+	if err != nil {
+		return err
+	}
+	if err = m.lock(ctx, session); err != nil {
+		return err
+	}
+	err = work(session)
+
+	//The lock is released even when ctx is done
+	if _, unlockErr := session.Query(gogm.NewLoadOptions(m.dbName), `MATCH (l:__GogmMigrationLock {Name: $name, Owner: $owner}) DELETE l`, map[string]interface{}{"name": lockName, "owner": m.owner}); err == nil {
+		err = unlockErr
+	}
+	return err
+}
+
+//lock acquires the lock of the database, waiting up to the lock timeout for another migrator to release it
+func (m *Migrator) lock(ctx context.Context, session gogm.Session) error {
+	deadline := time.Now().Add(m.LockTimeout)
+	for {
+		//Passing the lock type creates its uniqueness constraint in the database before the lock is merged
+		rows, err := session.QueryCtx(ctx, gogm.NewLoadOptions(m.dbName), `MERGE (l:__GogmMigrationLock {Name: $name}) ON CREATE SET l.Owner = $owner, l.AcquiredAt = datetime() RETURN l.Owner AS owner`, map[string]interface{}{"name": lockName, "owner": m.owner}, new(*migrationLock))
+		if err != nil {
+			return err
+		}
+		if len(rows) == 1 && rows[0]["owner"] == m.owner {
+			return nil
+		}
+		if !time.Now().Before(deadline) {
+			return ErrLocked
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(lockPollInterval):
+		}
+	}
+}
+
+//getApplied returns the applied migrations by version
+func (m *Migrator) getApplied(ctx context.Context, session gogm.Session) (map[int64]*migrationRecord, error) {
+	//Querying the records creates their uniqueness constraint in the database
+	var records []*migrationRecord
+	if err := session.QueryForObjectsCtx(ctx, gogm.NewLoadOptions(m.dbName), &records, `MATCH (m:__GogmMigration) RETURN m`, nil); err != nil {
+		return nil, err
+	}
+	applied := map[int64]*migrationRecord{}
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
+
+func newOwner() string {
+	owner := make([]byte, 8)
+	if _, err := rand.Read(owner); err != nil {
+		return time.Now().Format(time.RFC3339Nano)
+	}
+	return hex.EncodeToString(owner)
+}
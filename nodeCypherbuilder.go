@@ -2,6 +2,7 @@ package gogm
 
 import (
 	"strconv"
+	"strings"
 )
 
 type nodeQueryBuilder struct {
@@ -105,6 +106,91 @@ func (nqb nodeQueryBuilder) getCreate() (string, string, map[string]interface{},
 	return create, emptyString, nil, nil
 }
 
+//getMerge merges the node on its struct labels and custom ID. Nodes without a custom ID
+//or with a zero custom ID can't be identified in the database and are created
+func (nqb nodeQueryBuilder) getMerge(dbName string) (string, string, map[string]interface{}, map[string]graph, error) {
+	metadata, err := nqb.registry.get(nqb.n.getValue().Type(), dbName)
+	if err != nil {
+		return emptyString, emptyString, nil, nil, err
+	}
+	var (
+		nSign                                       = nqb.n.getSignature()
+		customIDPropertyName, customIDPropertyValue = metadata.getCustomID(*nqb.n.getValue())
+		idCQLRef                                    = nSign + "ID"
+	)
+	if customIDPropertyName == emptyString || customIDPropertyValue.IsZero() {
+		create, _, parameters, dependencies := nqb.getCreate()
+		return create, emptyString, parameters, dependencies, nil
+	}
+
+	merge := `MERGE (` + nSign + `:` + metadata.getStructLabel() + ` {` + customIDPropertyName + `: $` + idCQLRef + `})
+	`
+	return merge, emptyString, map[string]interface{}{idCQLRef: customIDPropertyValue.Interface()}, nil, nil
+}
+
+//getUnwindCreate returns the statement creating a batch of nodes of the same labels and the row
+//of the node in the batch. With merge, nodes having a custom ID are merged on it
+func (nqb nodeQueryBuilder) getUnwindCreate(merge bool, dbName string) (string, map[string]interface{}, error) {
+	row := map[string]interface{}{"ref": nqb.n.getID(), "properties": getWritableProperties(nqb.n.getProperties())}
+	create := `CREATE (n:` + nqb.n.getLabel() + `)
+	`
+	if merge {
+		metadata, err := nqb.registry.get(nqb.n.getValue().Type(), dbName)
+		if err != nil {
+			return emptyString, nil, err
+		}
+		if customIDPropertyName, customIDPropertyValue := metadata.getCustomID(*nqb.n.getValue()); customIDPropertyName != emptyString && !customIDPropertyValue.IsZero() {
+			create = `MERGE (n:` + metadata.getStructLabel() + ` {` + customIDPropertyName + `: row.customID})
+	SET n:` + nqb.n.getLabel() + `
+	`
+			row["customID"] = customIDPropertyValue.Interface()
+		}
+	}
+	return `UNWIND $rows AS row
+	` + create + `SET n += row.properties
+	RETURN row.ref, ID(n)`, row, nil
+}
+
+//getUnwindSet returns the statement updating a batch of stored nodes and the row of the node in the batch.
+//The statement is empty when neither the properties nor the labels of the node changed. The statement of
+//versioned nodes returns the new version of the nodes whose version matched
+func (nqb nodeQueryBuilder) getUnwindSet(dbName string) (string, map[string]interface{}, error) {
+	properties := getWritableProperties(nqb.deltaProperties)
+	if len(properties) == 0 && !nqb.isLabelsDirty {
+		return emptyString, nil, nil
+	}
+	versionPropertyName, version, err := getGraphVersion(nqb.n, nqb.registry, dbName)
+	if err != nil {
+		return emptyString, nil, err
+	}
+	var (
+		row                      = map[string]interface{}{"id": nqb.n.getID(), "properties": properties}
+		lock, increment, _return string
+	)
+	if versionPropertyName != emptyString {
+		lock = ` AND coalesce(n.` + versionPropertyName + `, 0) = row.version`
+		increment = `SET n.` + versionPropertyName + ` = coalesce(n.` + versionPropertyName + `, 0) + 1
+	`
+		_return = `RETURN row.id, n.` + versionPropertyName
+		row["version"] = version
+	}
+	set := `UNWIND $rows AS row
+	MATCH (n) WHERE ID(n) = row.id` + lock + `
+	SET n += row.properties
+	`
+	if nqb.isLabelsDirty {
+		set += `SET n:` + nqb.n.getLabel() + `
+	`
+	}
+	return set + increment + _return, row, nil
+}
+
+func (nqb nodeQueryBuilder) getUnwindDelete() (string, map[string]interface{}) {
+	return `UNWIND $rows AS row
+	MATCH (n) WHERE ID(n) = row.id
+	DETACH DELETE n`, map[string]interface{}{"id": nqb.n.getID()}
+}
+
 func (nqb nodeQueryBuilder) getMatch(dbName string) (string, map[string]interface{}, map[string]graph) {
 	var (
 		nSign                                       = nqb.n.getSignature()
@@ -125,6 +211,10 @@ func (nqb nodeQueryBuilder) getMatch(dbName string) (string, map[string]interfac
 
 	return match + filter, parameters, nil
 }
+func (nqb nodeQueryBuilder) getLock(dbName string) (string, string, map[string]interface{}, error) {
+	return getLock(nqb.n, `AND`, nqb.registry, dbName)
+}
+
 func (nqb nodeQueryBuilder) getSet() (string, map[string]interface{}) {
 
 	var (
@@ -154,37 +244,53 @@ func (nqb nodeQueryBuilder) getSet() (string, map[string]interface{}) {
 	return set, parameters
 }
 
-func (nqb nodeQueryBuilder) getLoadAll(IDs interface{}, lo *LoadOptions) (string, map[string]interface{}) {
+func (nqb nodeQueryBuilder) getLoadAll(IDs interface{}, lo *LoadOptions) (string, map[string]interface{}, error) {
 
+	metadata, err := nqb.registry.get(nqb.n.getValue().Type(), lo.DatabaseName)
+	if err != nil {
+		return emptyString, nil, err
+	}
 	var (
 		depth                   = strconv.Itoa(lo.Depth)
-		metadata, _             = nqb.registry.get(nqb.n.getValue().Type(), lo.DatabaseName)
 		customIDPropertyName, _ = metadata.getCustomID(*nqb.n.getValue())
-		parameters              = map[string]interface{}{}
+		filters                 []string
 	)
 	if lo.Depth == infiniteDepth {
 		depth = emptyString
 	}
 
-	match := `MATCH path = (n:` + nqb.n.getLabel() + `)-[*0..` + depth + `]-()
-	`
-	var filter string
+	clauses, err := getLoadAllClauses("n", metadata, lo)
+	if err != nil {
+		return emptyString, nil, err
+	}
+	parameters := clauses.parameters
+
 	if IDs != nil {
-		filter = `WHERE ID(n) IN $ids 
-		`
+		filter := `ID(n) IN $ids`
 		if customIDPropertyName != emptyString {
-			filter = `WHERE n.` + customIDPropertyName + ` IN $ids 
-			`
+			filter = `n.` + customIDPropertyName + ` IN $ids`
 		}
+		filters = append(filters, filter)
 		parameters["ids"] = IDs
 	}
+	filters = append(filters, clauses.filters...)
+
+	match := `MATCH (n:` + nqb.n.getLabel() + `)
+	`
+	if len(filters) > 0 {
+		match += `WHERE ` + strings.Join(filters, ` AND `) + `
+	`
+	}
+	match += `WITH n
+	` + clauses.orderBy + clauses.pagination + `MATCH path = (n)-[*0..` + depth + `]-()
+	`
 
 	end := `WITH n, path, range(0, length(path) - 1) as index
 	WITH  n, path, index, [i in index | CASE WHEN nodes(path)[i] = startNode(relationships(path)[i]) THEN false ELSE true END] as isDirectionInverted
 	RETURN path, ID(n), isDirectionInverted
-	`
+	` + clauses.orderBy
 
-	return match + filter + end, parameters
+	return match + end, parameters, nil
 }
 
 func (nqb nodeQueryBuilder) getDelete(dbName string) (string, map[string]interface{}, map[string]graph) {
@@ -194,10 +300,27 @@ func (nqb nodeQueryBuilder) getDelete(dbName string) (string, map[string]interfa
 	return match + delete, parameters, nil
 }
 
+//getSoftDelete marks the node as deleted by setting its soft delete property instead of deleting it
+func (nqb nodeQueryBuilder) getSoftDelete(softDeleteName string, softDeleted interface{}) (string, map[string]interface{}) {
+	return `MATCH (n) WHERE ID(n) = $id
+	SET n.` + softDeleteName + ` = $softDeleted
+	RETURN ID(n)`, map[string]interface{}{"id": nqb.n.getID(), "softDeleted": softDeleted}
+}
+
 func (nqb nodeQueryBuilder) getDeleteAll() (string, map[string]interface{}) {
 	return `MATCH (n:` + nqb.n.getLabel() + `) DETACH DELETE n RETURN ID(n)`, nil
 }
 
-func (nqb nodeQueryBuilder) getCountEntitiesOfType() (string, map[string]interface{}) {
-	return `MATCH (n:` + nqb.n.getLabel() + `) RETURN count(n) as count`, nil
+func (nqb nodeQueryBuilder) getCountEntitiesOfType(lo *LoadOptions) (string, map[string]interface{}, error) {
+	match := `MATCH (n:` + nqb.n.getLabel() + `) `
+	if !lo.IncludeSoftDeleted {
+		metadata, err := nqb.registry.get(nqb.n.getValue().Type(), lo.DatabaseName)
+		if err != nil {
+			return emptyString, nil, err
+		}
+		if filter, parameters := getSoftDeleteFilter("n", metadata); filter != emptyString {
+			return match + `WHERE ` + filter + ` RETURN count(n) as count`, parameters, nil
+		}
+	}
+	return match + `RETURN count(n) as count`, nil, nil
 }
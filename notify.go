@@ -22,10 +22,10 @@
 
 package gogm
 
-func notifyPreSaveGraph(g graph, eventer eventer, registry *registry, dbName string) error {
+func notifyPreSaveGraph(g graph, eventer *eventer, registry *registry, dbName string) error {
 
 	if g.getValue().IsValid() {
-		for _, eventListener := range eventer.eventListeners {
+		for _, eventListener := range eventer.listeners() {
 			eventListener.OnPreSave(event{g.getValue(), -1})
 		}
 
@@ -48,19 +48,19 @@ func notifyPreSaveGraph(g graph, eventer eventer, registry *registry, dbName str
 	return nil
 }
 
-func notifyPostSave(eventer eventer, g graph, lifeCycle lifeCycle) error {
+func notifyPostSave(eventer *eventer, g graph, lifeCycle lifeCycle) error {
 	if g == nil {
 		return nil
 	}
 	if g.getValue().IsValid() {
-		for _, eventListener := range eventer.eventListeners {
+		eventer.post(func(eventListener EventListener) {
 			eventListener.OnPostSave(event{g.getValue(), lifeCycle})
-		}
+		})
 	}
 	return nil
 }
 
-func notifyPostDelete(eventer eventer, g graph, lifeCycle lifeCycle) error {
+func notifyPostDelete(eventer *eventer, g graph, lifeCycle lifeCycle) error {
 	if g == nil {
 		return nil
 	}
@@ -76,9 +76,9 @@ func notifyPostDelete(eventer eventer, g graph, lifeCycle lifeCycle) error {
 
 	//send notice
 	if g.getValue().IsValid() {
-		for _, eventListener := range eventer.eventListeners {
+		eventer.post(func(eventListener EventListener) {
 			eventListener.OnPostDelete(event{g.getValue(), lifeCycle})
-		}
+		})
 	}
 
 	return nil
@@ -22,21 +22,54 @@
 
 package gogm
 
-//LoadOptions represents options used for loading database objects
+//LoadOptions represents options used for loading database objects.
+//Filters, OrderBy, Skip and Limit apply to the root entities of LoadAll. A zero Limit means no limit.
+//Soft deleted root entities are excluded unless IncludeSoftDeleted is set.
+//When DryRun is set, the load and query statements are collected into it instead of being run, queries
+//returning no records and counts 0.
+//When Profile is set, it's reset and filled with the plans of the load and query statements
 type LoadOptions struct {
-	Depth        int
-	DatabaseName string
+	Depth              int
+	DatabaseName       string
+	Filters            []Filter
+	OrderBy            []Order
+	Skip               int
+	Limit              int
+	IncludeSoftDeleted bool
+	DryRun             *DryRun
+	Profile            *Profile
 }
 
-//SaveOptions represents options used for saving database objects
+//SaveOptions represents options used for saving database objects.
+//With Merge, objects that aren't loaded in the session are merged on their custom ID instead of
+//being created, and new relationships are merged between their endpoints. This makes saving objects
+//received from external systems idempotent.
+//With a BatchSize greater than zero, objects are written with UNWIND statements grouping objects of the
+//same labels or relationship type, each sending at most BatchSize objects. Use it to save large slices.
+//When Stats is set, it's reset and filled with the stats of the statements run by the save.
+//When DryRun is set, the save statements are collected into it instead of being run.
+//When Profile is set, it's reset and filled with the plans of the save statements
 type SaveOptions struct {
 	Depth        int
 	DatabaseName string
+	Merge        bool
+	BatchSize    int
+	Stats        *WriteStats
+	DryRun       *DryRun
+	Profile      *Profile
 }
 
-//DeleteOptions represents options used for saving database objects. Currently, not applicatble to this version of the OGM
+//DeleteOptions represents options used for deleting database objects.
+//Deleting a node also deletes the nodes it owns through relationship fields tagged 'cascade:delete', and the nodes
+//they own in turn, up to Depth relationships away from the deleted node. Owned nodes are matched in the database,
+//whether they're loaded in the session or not. A zero or negative Depth has no limit, as with nil options.
+//When Stats is set, it's reset and filled with the stats of the statements run by the delete.
+//When DryRun is set, the delete statements are collected into it instead of being run
 type DeleteOptions struct {
 	DatabaseName string
+	Depth        int
+	Stats        *WriteStats
+	DryRun       *DryRun
 }
 
 //NewLoadOptions creates LoadOptions with defaults
@@ -47,6 +80,10 @@ func NewLoadOptions(dbName string) *LoadOptions {
 	return lo
 }
 
+func (lo *LoadOptions) restrictsRoots() bool {
+	return len(lo.Filters) > 0 || len(lo.OrderBy) > 0 || lo.Skip > 0 || lo.Limit > 0
+}
+
 //NewSaveOptions creates SaveOptions with defaults
 func NewSaveOptions(dbName string, depth int) *SaveOptions {
 	so := &SaveOptions{}
@@ -55,9 +92,10 @@ func NewSaveOptions(dbName string, depth int) *SaveOptions {
 	return so
 }
 
-//NewLoadOptions creates LoadOptions with defaults
+//NewDeleteOptions creates DeleteOptions with defaults
 func NewDeleteOptions(dbName string) *DeleteOptions {
 	lo := &DeleteOptions{}
 	lo.DatabaseName = dbName
+	lo.Depth = infiniteDepth
 	return lo
 }
@@ -0,0 +1,177 @@
+// MIT License
+//
+// Copyright (c) 2022 pmadhav
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package gogm
+
+import (
+	"context"
+	"strings"
+
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+)
+
+//Profile collects the plans of the statements run by an operation. Statements are prefixed with PROFILE, running
+//them and planning them with the rows and db hits of each operator, or with EXPLAIN when Explain is set. Explained
+//statements aren't run, hence the operation behaves as a dry run: the session store and the domain objects are
+//left unchanged
+type Profile struct {
+	Explain bool
+	Plans   []StatementPlan
+}
+
+//StatementPlan is the plan of a statement
+type StatementPlan struct {
+	Statement
+	Plan *Plan
+}
+
+//Plan is an operator of the plan of a statement, with the operators it reads from as children.
+//Rows, DbHits, PageCacheHits, PageCacheMisses and Time are only set by PROFILE
+type Plan struct {
+	Operator        string
+	Arguments       map[string]interface{}
+	Identifiers     []string
+	EstimatedRows   float64
+	Rows            int64
+	DbHits          int64
+	PageCacheHits   int64
+	PageCacheMisses int64
+	Time            int64
+	Children        []*Plan
+}
+
+type profileKey struct{}
+
+//withProfile returns a context collecting the plans of the statements run with it into profile, reset first
+func withProfile(ctx context.Context, profile *Profile) context.Context {
+	if profile == nil {
+		return ctx
+	}
+	profile.Plans = nil
+	return context.WithValue(ctx, profileKey{}, profile)
+}
+
+//getProfile returns the profile of ctx, nil when statements run with ctx aren't planned
+func getProfile(ctx context.Context) *Profile {
+	profile, _ := ctx.Value(profileKey{}).(*Profile)
+	return profile
+}
+
+//newAttemptProfile returns the profile of an attempt of a transaction function, nil when ctx doesn't have any.
+//Its plans are merged into the profile of ctx once the transaction function succeeds
+func newAttemptProfile(ctx context.Context) *Profile {
+	profile := getProfile(ctx)
+	if profile == nil {
+		return nil
+	}
+	return &Profile{Explain: profile.Explain}
+}
+
+func (p *Profile) explains() bool {
+	return p != nil && p.Explain
+}
+
+func (p *Profile) prefix() string {
+	if p.Explain {
+		return "EXPLAIN "
+	}
+	return "PROFILE "
+}
+
+//plan returns cql prefixed to be planned, cql itself when p is nil
+func (p *Profile) plan(cql string) string {
+	if p == nil {
+		return cql
+	}
+	return p.prefix() + cql
+}
+
+//consume adds the plan of the summary of result, whose records are collected, to the profile
+func (p *Profile) consume(result neo4j.Result) error {
+	if p == nil {
+		return nil
+	}
+	summary, err := result.Consume()
+	if err != nil {
+		return err
+	}
+	statementPlan := StatementPlan{
+		Statement: Statement{
+			Cypher:     strings.TrimPrefix(summary.Query().Text(), p.prefix()),
+			Parameters: summary.Query().Parameters()}}
+	if database := summary.Database(); database != nil {
+		statementPlan.DatabaseName = database.Name()
+	}
+	if profiledPlan := summary.Profile(); profiledPlan != nil {
+		statementPlan.Plan = newProfiledPlan(profiledPlan)
+	} else if plan := summary.Plan(); plan != nil {
+		statementPlan.Plan = newPlan(plan)
+	}
+	p.Plans = append(p.Plans, statementPlan)
+	return nil
+}
+
+func (p *Profile) merge(other *Profile) {
+	if p == nil || other == nil {
+		return
+	}
+	p.Plans = append(p.Plans, other.Plans...)
+}
+
+func newPlan(plan neo4j.Plan) *Plan {
+	p := &Plan{
+		Operator:      plan.Operator(),
+		Arguments:     plan.Arguments(),
+		Identifiers:   plan.Identifiers(),
+		EstimatedRows: getEstimatedRows(plan.Arguments())}
+	for _, child := range plan.Children() {
+		p.Children = append(p.Children, newPlan(child))
+	}
+	return p
+}
+
+func newProfiledPlan(plan neo4j.ProfiledPlan) *Plan {
+	p := &Plan{
+		Operator:        plan.Operator(),
+		Arguments:       plan.Arguments(),
+		Identifiers:     plan.Identifiers(),
+		EstimatedRows:   getEstimatedRows(plan.Arguments()),
+		Rows:            plan.Records(),
+		DbHits:          plan.DbHits(),
+		PageCacheHits:   plan.PageCacheHits(),
+		PageCacheMisses: plan.PageCacheMisses(),
+		Time:            plan.Time()}
+	for _, child := range plan.Children() {
+		p.Children = append(p.Children, newProfiledPlan(child))
+	}
+	return p
+}
+
+func getEstimatedRows(arguments map[string]interface{}) float64 {
+	switch estimatedRows := arguments["EstimatedRows"].(type) {
+	case float64:
+		return estimatedRows
+	case int64:
+		return float64(estimatedRows)
+	}
+	return 0
+}
@@ -30,7 +30,9 @@ import (
 
 var metaProperties = map[string]bool{"id": true}
 
-func unloadGraphProperties(g graph, propertyStructFields map[string]*reflect.StructField) {
+//unloadGraphProperties sets the property fields of the domain object of g to the properties of g, converted to the
+//types of the fields by driverPropertiesAsStructFieldValues
+func unloadGraphProperties(g graph, propertyStructFields map[string]*reflect.StructField) error {
 	if g.getValue().IsValid() {
 		for backendName, structField := range propertyStructFields {
 			propertyField := &field{
@@ -40,9 +42,16 @@ func unloadGraphProperties(g graph, propertyStructFields map[string]*reflect.Str
 			if g.getProperties()[backendName] == nil {
 				v = reflect.Zero(structField.Type)
 			}
+			if !v.Type().AssignableTo(structField.Type) {
+				return &ErrMapping{
+					Type:   g.getValue().Type(),
+					Field:  structField.Name,
+					Reason: "property '" + backendName + "' of type " + v.Type().String() + " can't be assigned to a field of type " + structField.Type.String()}
+			}
 			propertyField.getValue().Set(v)
 		}
 	}
+	return nil
 }
 
 func diffProperties(proposedProperties map[string]interface{}, storedProperties map[string]interface{}) map[string]interface{} {
@@ -123,11 +132,19 @@ func driverPropertiesAsStructFieldValues(driverProperties map[string]interface{}
 
 	for backendName, mapp := range mappedProperties {
 		structField := structFields[backendName]
+		if structField.Type.Kind() != reflect.Map || structField.Type.Key().Kind() != reflect.String {
+			continue
+		}
 		mapElem := structField.Type.Elem()
 		mapValue := reflect.MakeMapWithSize(structField.Type, len(mapp))
 		for key, value := range mapp {
-			mapValue.SetMapIndex(reflect.ValueOf(key), reflect.ValueOf(driverValueAsType(value, mapElem)))
-
+			elemValue := reflect.ValueOf(driverValueAsType(value, mapElem))
+			if !elemValue.IsValid() || !elemValue.Type().AssignableTo(mapElem) {
+				//Left to unloadGraphProperties to report
+				mapValue = reflect.ValueOf(mapp)
+				break
+			}
+			mapValue.SetMapIndex(reflect.ValueOf(key).Convert(structField.Type.Key()), elemValue)
 		}
 		driverProperties[backendName] = mapValue.Interface()
 	}
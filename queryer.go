@@ -23,7 +23,7 @@
 package gogm
 
 import (
-	"errors"
+	"context"
 	"fmt"
 	"reflect"
 	"sort"
@@ -42,7 +42,7 @@ func newQueryer(cypherExecutor *cypherExecuter, graphFactory graphFactory, regis
 	return &queryer{cypherExecutor, graphFactory, registry}
 }
 
-func (q *queryer) queryForObject(loadOptions *LoadOptions, object interface{}, cypher string, parameters map[string]interface{}) error {
+func (q *queryer) queryForObject(ctx context.Context, loadOptions *LoadOptions, object interface{}, cypher string, parameters map[string]interface{}) error {
 	var (
 		err      error
 		values   reflect.Value
@@ -62,7 +62,7 @@ func (q *queryer) queryForObject(loadOptions *LoadOptions, object interface{}, c
 	if label, err = metadata.getLabel(invalidValue); err != nil {
 		return err
 	}
-	if records, err = q.cypherExecuter.collect(dbName, cypher, parameters); err != nil {
+	if records, err = q.cypherExecuter.collect(ctx, dbName, cypher, parameters); err != nil {
 		return err
 	}
 
@@ -75,13 +75,13 @@ func (q *queryer) queryForObject(loadOptions *LoadOptions, object interface{}, c
 	}
 
 	if len(records) > 1 {
-		return errors.New("result contains more than one record")
+		return newKindError(ErrMultipleResults, "result contains more than one record")
 	}
 
 	return nil
 }
 
-func (q *queryer) queryForObjects(loadOptions *LoadOptions, objects interface{}, cypher string, parameters map[string]interface{}) error {
+func (q *queryer) queryForObjects(ctx context.Context, loadOptions *LoadOptions, objects interface{}, cypher string, parameters map[string]interface{}) error {
 
 	var (
 		err      error
@@ -106,7 +106,7 @@ func (q *queryer) queryForObjects(loadOptions *LoadOptions, objects interface{},
 		return err
 	}
 
-	if records, err = q.cypherExecuter.collect(dbName, cypher, parameters); err != nil {
+	if records, err = q.cypherExecuter.collect(ctx, dbName, cypher, parameters); err != nil {
 		return err
 	}
 
@@ -117,7 +117,7 @@ func (q *queryer) queryForObjects(loadOptions *LoadOptions, objects interface{},
 	return nil
 }
 
-func (q *queryer) query(loadOptions *LoadOptions, cypher string, parameters map[string]interface{}, objects ...interface{}) ([]map[string]interface{}, error) {
+func (q *queryer) query(ctx context.Context, loadOptions *LoadOptions, cypher string, parameters map[string]interface{}, objects ...interface{}) ([]map[string]interface{}, error) {
 	var dbName string = ""
 	if loadOptions != nil {
 		dbName = loadOptions.DatabaseName
@@ -130,7 +130,7 @@ func (q *queryer) query(loadOptions *LoadOptions, cypher string, parameters map[
 		}
 	}
 
-	records, err := q.cypherExecuter.collect(dbName, cypher, parameters)
+	records, err := q.cypherExecuter.collect(ctx, dbName, cypher, parameters)
 	if err != nil {
 		return nil, err
 	}
@@ -158,8 +158,10 @@ func (q *queryer) query(loadOptions *LoadOptions, cypher string, parameters map[
 								if len(names) > 0 {
 									name = names[0]
 								}
-								for _, runtimeLabel := range properties[name].([]interface{}) {
-									var index = indexOfString(nodeLabels, runtimeLabel.(string))
+								runtimeLabels, _ := properties[name].([]interface{})
+								for _, runtimeLabel := range runtimeLabels {
+									label, _ := runtimeLabel.(string)
+									var index = indexOfString(nodeLabels, label)
 									if index > -1 {
 										nodeLabels = removeStringAt(nodeLabels, index)
 									}
@@ -174,7 +176,9 @@ func (q *queryer) query(loadOptions *LoadOptions, cypher string, parameters map[
 									properties: neo4jNode.Props}
 								g.getProperties()[idPropertyName] = neo4jNode.Id
 								driverPropertiesAsStructFieldValues(g.getProperties(), nodeMetadata.getPropertyStructFields())
-								unloadGraphProperties(g, nodeMetadata.getPropertyStructFields())
+								if err := unloadGraphProperties(g, nodeMetadata.getPropertyStructFields()); err != nil {
+									return nil, err
+								}
 								break
 							}
 
@@ -185,7 +189,7 @@ func (q *queryer) query(loadOptions *LoadOptions, cypher string, parameters map[
 					}
 				}
 				if g == nil {
-					return nil, errors.New(fmt.Sprint("Not found: Runtime object for Node with id:", neo4jNode.Id, " and label:", strings.Join(neo4jNode.Labels, labelsDelim)))
+					return nil, &ErrMapping{Reason: fmt.Sprint("Not found: Runtime object for Node with id:", neo4jNode.Id, " and label:", strings.Join(neo4jNode.Labels, labelsDelim))}
 				}
 				columns[key] = g.getValue().Interface()
 			} else if neo4jRelationship, isNeo4jRelationship := record.Values[index].(neo4j.Relationship); isNeo4jRelationship {
@@ -202,13 +206,15 @@ func (q *queryer) query(loadOptions *LoadOptions, cypher string, parameters map[
 								properties: neo4jRelationship.Props}
 							g.getProperties()[idPropertyName] = neo4jRelationship.Id
 							driverPropertiesAsStructFieldValues(g.getProperties(), relationshipMetadata.getPropertyStructFields())
-							unloadGraphProperties(g, relationshipMetadata.getPropertyStructFields())
+							if err := unloadGraphProperties(g, relationshipMetadata.getPropertyStructFields()); err != nil {
+								return nil, err
+							}
 							break
 						}
 					}
 				}
 				if g == nil {
-					return nil, errors.New(fmt.Sprint("Not found: Runtime object for Node with id:", neo4jRelationship.Id, " and type:", neo4jRelationship.Type))
+					return nil, &ErrMapping{Reason: fmt.Sprint("Not found: Runtime object for Relationship with id:", neo4jRelationship.Id, " and type:", neo4jRelationship.Type)}
 				}
 				columns[key] = g.getValue().Interface()
 			} else {
@@ -233,13 +239,17 @@ func (q *queryer) getObjectsFromRecords(domainObjectType reflect.Type, metadata
 	ptrToObjs := reflect.New(sliceOfPtrToObjs.Type())
 
 	for _, record := range records {
-		column0 := record.Values[0]
+		g = nil
+		var column0 interface{}
+		if len(record.Values) > 0 {
+			column0 = record.Values[0]
+		}
 		newPtrToDomainObject := reflect.New(domainObjectType.Elem())
 
 		if neo4jNode, isNeo4jNode := column0.(neo4j.Node); isNeo4jNode {
 
 			if internalGraphEntityType != typeOfPrivateNode {
-				return invalidValue, errors.New("expecting a Relationship, but got a Node from the query response")
+				return invalidValue, &ErrMapping{Type: domainObjectType, Reason: "expecting a Relationship, but got a Node from the query response"}
 			}
 			nodeMetadata := metadata.(*nodeMetadata)
 			labels := neo4jNode.Labels
@@ -255,7 +265,7 @@ func (q *queryer) getObjectsFromRecords(domainObjectType reflect.Type, metadata
 
 		if neo4jRelationship, isNeo4jRelationship := column0.(neo4j.Relationship); isNeo4jRelationship {
 			if internalGraphEntityType != typeOfPrivateRelationship {
-				return invalidValue, errors.New("unexpected graph type. Expecting a Node, but got a Relationship from the query response")
+				return invalidValue, &ErrMapping{Type: domainObjectType, Reason: "unexpected graph type. Expecting a Node, but got a Relationship from the query response"}
 			}
 			g = &relationship{
 				ID:         neo4jRelationship.Id,
@@ -264,22 +274,27 @@ func (q *queryer) getObjectsFromRecords(domainObjectType reflect.Type, metadata
 			g.getProperties()[idPropertyName] = neo4jRelationship.Id
 			entityLabel = neo4jRelationship.Type
 		}
+		if g == nil {
+			return invalidValue, &ErrMapping{Type: domainObjectType, Reason: "expecting a Node or a Relationship in the first column of the query response"}
+		}
 		g.setValue(&newPtrToDomainObject)
 		g.setLabel(label)
 
 		if label != entityLabel {
-			return invalidValue, errors.New("label '" + label + "' from `" + domainObjectType.String() + "` don't match with label `" + entityLabel + "` from query result")
+			return invalidValue, &ErrMapping{Type: domainObjectType, Reason: "label '" + label + "' from `" + domainObjectType.String() + "` don't match with label `" + entityLabel + "` from query result"}
 		}
 
 		ptrToObjs.Elem().Set(reflect.Append(ptrToObjs.Elem(), newPtrToDomainObject))
 		driverPropertiesAsStructFieldValues(g.getProperties(), metadata.getPropertyStructFields())
-		unloadGraphProperties(g, metadata.getPropertyStructFields())
+		if err := unloadGraphProperties(g, metadata.getPropertyStructFields()); err != nil {
+			return invalidValue, err
+		}
 	}
 
 	return ptrToObjs.Elem(), nil
 }
 
-func (q *queryer) countEntitiesOfType(loadOptions *LoadOptions, object interface{}) (int64, error) {
+func (q *queryer) countEntitiesOfType(ctx context.Context, loadOptions *LoadOptions, object interface{}) (int64, error) {
 
 	var (
 		value         = reflect.ValueOf(object)
@@ -295,6 +310,8 @@ func (q *queryer) countEntitiesOfType(loadOptions *LoadOptions, object interface
 
 	if loadOptions != nil {
 		dbName = loadOptions.DatabaseName
+	} else {
+		loadOptions = NewLoadOptions(dbName)
 	}
 
 	//object: **DomainObject
@@ -305,21 +322,25 @@ func (q *queryer) countEntitiesOfType(loadOptions *LoadOptions, object interface
 	if cypherBuilder, err = newCypherBuilder(graphs[0], q.registry, nil, dbName); err != nil {
 		return -1, err
 	}
-	cypher, parameters = cypherBuilder.getCountEntitiesOfType()
+	if cypher, parameters, err = cypherBuilder.getCountEntitiesOfType(loadOptions); err != nil {
+		return -1, err
+	}
 
 	if cypher != emptyString {
-		if record, err = q.cypherExecuter.single(dbName, cypher, parameters); err != nil {
+		if record, err = q.cypherExecuter.single(ctx, dbName, cypher, parameters); err != nil {
 			return -1, err
 		}
 		if record != nil {
-			count = record.Values[0].(int64)
+			if count, err = getCount(record, value.Elem().Type()); err != nil {
+				return -1, err
+			}
 		}
 	}
 
 	return count, nil
 }
 
-func (q *queryer) count(loadOptions *LoadOptions, cypher string, parameters map[string]interface{}) (int64, error) {
+func (q *queryer) count(ctx context.Context, loadOptions *LoadOptions, cypher string, parameters map[string]interface{}) (int64, error) {
 	var (
 		record *neo4j.Record
 		err    error
@@ -328,8 +349,23 @@ func (q *queryer) count(loadOptions *LoadOptions, cypher string, parameters map[
 	if loadOptions != nil {
 		dbName = loadOptions.DatabaseName
 	}
-	if record, err = q.cypherExecuter.single(dbName, cypher, parameters); err != nil {
+	if record, err = q.cypherExecuter.single(ctx, dbName, cypher, parameters); err != nil {
 		return -1, err
 	}
-	return record.Values[0].(int64), nil
+	if record == nil {
+		//A dry run returns no record
+		return 0, nil
+	}
+	return getCount(record, nil)
+}
+
+//getCount returns the count in the first column of record. domainObjectType is the type of the counted domain
+//objects, if any
+func getCount(record *neo4j.Record, domainObjectType reflect.Type) (int64, error) {
+	if len(record.Values) > 0 {
+		if count, isInt64 := record.Values[0].(int64); isInt64 {
+			return count, nil
+		}
+	}
+	return -1, &ErrMapping{Type: domainObjectType, Reason: "expecting an integer count in the first column of the query response"}
 }
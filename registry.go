@@ -23,9 +23,11 @@
 package gogm
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 	"sync"
 )
@@ -38,14 +40,26 @@ type registry struct {
 
 	cypherExecuter cypherExecuter
 	objectsMu      sync.Mutex
+
+	dialect   SchemaDialect
+	dialectMu sync.Mutex
+
+	pendingSchemas   map[string][]metadata //metadata whose schema isn't created yet, by database
+	pendingSchemasMu sync.Mutex
 }
 
-func newRegistry(cypherExecuter cypherExecuter) *registry {
+func newRegistry(cypherExecuter cypherExecuter, dialect SchemaDialect) *registry {
 	registered := map[reflect.Type]map[string]metadata{}
 	registered[reflect.TypeOf(&nodeMetadata{})] = map[string]metadata{}
 	registered[reflect.TypeOf(&relationshipMetadata{})] = map[string]metadata{}
 
-	return &registry{map[string]metadata{}, map[string][]metadata{}, registered, cypherExecuter, sync.Mutex{}}
+	return &registry{
+		objects:        map[string]metadata{},
+		labels:         map[string][]metadata{},
+		registered:     registered,
+		cypherExecuter: cypherExecuter,
+		dialect:        dialect,
+		pendingSchemas: map[string][]metadata{}}
 }
 
 func (r *registry) get(t reflect.Type, dbName string) (metadata, error) {
@@ -55,36 +69,197 @@ func (r *registry) get(t reflect.Type, dbName string) (metadata, error) {
 		if m, err = getMetadata(t, r, dbName); err != nil {
 			return nil, err
 		}
-		r.setMetadata(t.String(), m)
-		for _, label := range strings.Split(m.getStructLabel(), labelsDelim) {
-			r.labels[label] = append(r.labels[label], m)
+		if m, _, err = r.register(t, m); err != nil {
+			return m, err
 		}
-		if r.registered[reflect.TypeOf(m)][m.getStructLabel()] != nil {
-			return nil, errors.New(fmt.Sprint("Duplicate labels for an entity type. Type ", r.registered[reflect.TypeOf(m)][m.getStructLabel()].getType().String(), " with label ", r.registered[reflect.TypeOf(m)][m.getStructLabel()].getStructLabel(), " conflicts with ", m.getType().String(), " with label ", m.getStructLabel()))
+	}
+	r.postponeSchema(m, dbName)
+	return m, nil
+}
+
+//postponeSchema postpones creating the schema of m in dbName, unless it's created, until statements run in dbName
+func (r *registry) postponeSchema(m metadata, dbName string) {
+	if m.isSchemaCreated(dbName) {
+		return
+	}
+	r.pendingSchemasMu.Lock()
+	defer r.pendingSchemasMu.Unlock()
+	for _, pending := range r.pendingSchemas[dbName] {
+		if pending == m {
+			return
 		}
-		r.registered[reflect.TypeOf(m)][m.getStructLabel()] = m
-		for _, statement := range getCreateSchemaStatement(m) {
-			if _, err = r.cypherExecuter.exec(dbName, statement, nil, false, false); err != nil {
-				return nil, err
+	}
+	r.pendingSchemas[dbName] = append(r.pendingSchemas[dbName], m)
+}
+
+//createSchemas creates the postponed schemas of dbName with the deadline of ctx. They stay postponed when the
+//statements run with ctx have no effects, e.g. on a dry run
+func (r *registry) createSchemas(ctx context.Context, dbName string) error {
+	if isDryRun(ctx) {
+		return nil
+	}
+	r.pendingSchemasMu.Lock()
+	pending := append([]metadata(nil), r.pendingSchemas[dbName]...)
+	r.pendingSchemasMu.Unlock()
+	if len(pending) == 0 {
+		return nil
+	}
+
+	ctx = schemaContext{ctx}
+	for _, m := range pending {
+		//Concurrent callers wait for the schema being created by another
+		if err := m.initSchema(dbName, func() error {
+			dialect, err := r.getSchemaDialect(ctx, dbName)
+			if err != nil {
+				return err
 			}
+			for _, statement := range getCreateSchemaStatement(m, dialect) {
+				if _, err = r.cypherExecuter.exec(ctx, dbName, statement, nil, false, false); err != nil {
+					return err
+				}
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+
+	r.pendingSchemasMu.Lock()
+	defer r.pendingSchemasMu.Unlock()
+	var stillPending []metadata
+	for _, m := range r.pendingSchemas[dbName] {
+		if !m.isSchemaCreated(dbName) {
+			stillPending = append(stillPending, m)
 		}
+	}
+	r.pendingSchemas[dbName] = stillPending
+	return nil
+}
+
+//schemaContext is the context of the statements creating schemas. It has the deadline of the operation they're
+//created for, but not its dry run, profile and stats
+type schemaContext struct {
+	context.Context
+}
 
+func (c schemaContext) Value(key interface{}) interface{} {
+	switch key.(type) {
+	case dryRunKey, profileKey, writeStatsKey:
+		return nil
 	}
-	return m, err
+	return c.Context.Value(key)
 }
 
-func (r *registry) getMetadata(id string) metadata {
+//register registers m, the metadata of t. Metadata is built without holding the lock since building it
+//registers the metadata of related types, hence t may have been registered concurrently. In which case
+//the registered metadata is returned and m is discarded
+func (r *registry) register(t reflect.Type, m metadata) (metadata, bool, error) {
 	r.objectsMu.Lock()
 	defer r.objectsMu.Unlock()
-	return r.objects[id]
+
+	if registered := r.objects[t.String()]; registered != nil {
+		return registered, false, nil
+	}
+	if registered := r.registered[reflect.TypeOf(m)][m.getStructLabel()]; registered != nil {
+		return nil, false, errors.New(fmt.Sprint("Duplicate labels for an entity type. Type ", registered.getType().String(), " with label ", registered.getStructLabel(), " conflicts with ", m.getType().String(), " with label ", m.getStructLabel()))
+	}
+	r.objects[t.String()] = m
+	for _, label := range strings.Split(m.getStructLabel(), labelsDelim) {
+		r.labels[label] = append(r.labels[label], m)
+	}
+	r.registered[reflect.TypeOf(m)][m.getStructLabel()] = m
+	return m, true, nil
 }
 
-func (r *registry) setMetadata(id string, metadata metadata) {
+//getSchemaDialect returns the configured schema dialect. When it isn't configured, it's detected once
+func (r *registry) getSchemaDialect(ctx context.Context, dbName string) (SchemaDialect, error) {
+	r.dialectMu.Lock()
+	defer r.dialectMu.Unlock()
+
+	if r.dialect == DetectSchemaDialect {
+		dialect, err := detectSchemaDialect(ctx, &r.cypherExecuter, dbName)
+		if err != nil {
+			return DetectSchemaDialect, err
+		}
+		r.dialect = dialect
+	}
+	return r.dialect, nil
+}
+
+//diffSchema compares the constraints and indexes declared by the registered domain objects with those of the database
+func (r *registry) diffSchema(ctx context.Context, dbName string) (SchemaReport, SchemaDialect, error) {
+	dialect, err := r.getSchemaDialect(ctx, dbName)
+	if err != nil {
+		return SchemaReport{}, dialect, err
+	}
+	actual, err := getDatabaseSchema(ctx, &r.cypherExecuter, dbName)
+	if err != nil {
+		return SchemaReport{}, dialect, err
+	}
+	return diffSchema(getDeclaredSchema(r.all(), dialect), actual), dialect, nil
+}
+
+//applySchema creates the missing constraints and indexes and, depending on schemaApplyOptions, drops the extra ones
+//and replaces the mismatched ones. Items are dropped first, so that a replaced item can be created under its name
+func (r *registry) applySchema(ctx context.Context, dbName string, schemaApplyOptions *SchemaApplyOptions) (SchemaReport, error) {
+	var statements []string
+
+	if schemaApplyOptions == nil {
+		schemaApplyOptions = &SchemaApplyOptions{}
+	}
+	report, dialect, err := r.diffSchema(ctx, dbName)
+	if err != nil {
+		return report, err
+	}
+	if schemaApplyOptions.DropExtra {
+		for _, item := range report.Extra {
+			statements = append(statements, getDropSchemaItemStatement(item))
+		}
+	}
+	if schemaApplyOptions.ReplaceMismatched {
+		for _, mismatch := range report.Mismatched {
+			statements = append(statements, getDropSchemaItemStatement(mismatch.Actual))
+		}
+		for _, mismatch := range report.Mismatched {
+			statements = append(statements, getCreateSchemaItemStatement(mismatch.Declared, dialect))
+		}
+	}
+	for _, item := range report.Missing {
+		statements = append(statements, getCreateSchemaItemStatement(item, dialect))
+	}
+	for _, statement := range statements {
+		if _, err = r.cypherExecuter.exec(ctx, dbName, statement, nil, false, false); err != nil {
+			return report, err
+		}
+	}
+	return report, nil
+}
+
+//all returns the metadata of the registered domain objects in the order of their types
+func (r *registry) all() []metadata {
+	r.objectsMu.Lock()
+	defer r.objectsMu.Unlock()
+
+	var ids []string
+	for id := range r.objects {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	metadatas := make([]metadata, 0, len(ids))
+	for _, id := range ids {
+		metadatas = append(metadatas, r.objects[id])
+	}
+	return metadatas
+}
+
+func (r *registry) getMetadata(id string) metadata {
 	r.objectsMu.Lock()
 	defer r.objectsMu.Unlock()
-	r.objects[id] = metadata
+	return r.objects[id]
 }
 
 func (r *registry) getLabelMetadatas(label string) []metadata {
-	return r.labels[label]
+	r.objectsMu.Lock()
+	defer r.objectsMu.Unlock()
+	return append([]metadata(nil), r.labels[label]...)
 }
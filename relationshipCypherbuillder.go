@@ -24,6 +24,7 @@ package gogm
 
 import (
 	"strconv"
+	"strings"
 )
 
 type relationshipQueryBuilder struct {
@@ -70,6 +71,102 @@ func (rqb relationshipQueryBuilder) getCreate() (string, string, map[string]inte
 	return "", create, nil, map[string]graph{startSign: r.nodes[startNode], endSign: r.nodes[endNode]}
 }
 
+//getMerge merges the relationship between its endpoints on its type and, when the relationship
+//entity has one, its custom ID
+func (rqb relationshipQueryBuilder) getMerge(dbName string) (string, string, map[string]interface{}, map[string]graph, error) {
+	var (
+		r          = rqb.r
+		startSign  = r.nodes[startNode].getSignature()
+		endSign    = r.nodes[endNode].getSignature()
+		rSign      = r.getSignature()
+		idCQLRef   = rSign + "ID"
+		identity   string
+		parameters map[string]interface{}
+	)
+	if r.getValue() != nil && r.getValue().IsValid() {
+		metadata, err := rqb.registry.get(r.getValue().Type(), dbName)
+		if err != nil {
+			return emptyString, emptyString, nil, nil, err
+		}
+		if customIDPropertyName, customIDPropertyValue := metadata.getCustomID(*r.getValue()); customIDPropertyName != emptyString && !customIDPropertyValue.IsZero() {
+			identity = ` {` + customIDPropertyName + `: $` + idCQLRef + `}`
+			parameters = map[string]interface{}{idCQLRef: customIDPropertyValue.Interface()}
+		}
+	}
+	merge := `MERGE (` + startSign + `)-[` + rSign + `:` + r.getType() + identity + `]->(` + endSign + `)
+	`
+	return "", merge, parameters, map[string]graph{startSign: r.nodes[startNode], endSign: r.nodes[endNode]}, nil
+}
+
+//getUnwindCreate returns the statement creating a batch of relationships of the same type and the row
+//of the relationship in the batch. With merge, relationships are merged between their endpoints
+func (rqb relationshipQueryBuilder) getUnwindCreate(merge bool, dbName string) (string, map[string]interface{}, error) {
+	var (
+		r   = rqb.r
+		row = map[string]interface{}{
+			"ref":        r.getID(),
+			"start":      r.nodes[startNode].getID(),
+			"end":        r.nodes[endNode].getID(),
+			"properties": getWritableProperties(r.getProperties())}
+		create = `CREATE (s)-[r:` + r.getType() + `]->(e)
+	`
+	)
+	if merge {
+		identity := emptyString
+		if r.getValue() != nil && r.getValue().IsValid() {
+			metadata, err := rqb.registry.get(r.getValue().Type(), dbName)
+			if err != nil {
+				return emptyString, nil, err
+			}
+			if customIDPropertyName, customIDPropertyValue := metadata.getCustomID(*r.getValue()); customIDPropertyName != emptyString && !customIDPropertyValue.IsZero() {
+				identity = ` {` + customIDPropertyName + `: row.customID}`
+				row["customID"] = customIDPropertyValue.Interface()
+			}
+		}
+		create = `MERGE (s)-[r:` + r.getType() + identity + `]->(e)
+	`
+	}
+	return `UNWIND $rows AS row
+	MATCH (s) WHERE ID(s) = row.start
+	MATCH (e) WHERE ID(e) = row.end
+	` + create + `SET r += row.properties
+	RETURN row.ref, ID(r)`, row, nil
+}
+
+//getUnwindSet returns the statement updating a batch of stored relationships and the row of the
+//relationship in the batch. The statement is empty when the properties of the relationship didn't change.
+//The statement of versioned relationships returns the new version of the relationships whose version matched
+func (rqb relationshipQueryBuilder) getUnwindSet(dbName string) (string, map[string]interface{}, error) {
+	properties := getWritableProperties(rqb.deltaProperties)
+	if len(properties) == 0 {
+		return emptyString, nil, nil
+	}
+	versionPropertyName, version, err := getGraphVersion(rqb.r, rqb.registry, dbName)
+	if err != nil {
+		return emptyString, nil, err
+	}
+	var (
+		row             = map[string]interface{}{"id": rqb.r.getID(), "properties": properties}
+		lock, increment string
+	)
+	if versionPropertyName != emptyString {
+		lock = ` AND coalesce(r.` + versionPropertyName + `, 0) = row.version`
+		increment = `
+	SET r.` + versionPropertyName + ` = coalesce(r.` + versionPropertyName + `, 0) + 1
+	RETURN row.id, r.` + versionPropertyName
+		row["version"] = version
+	}
+	return `UNWIND $rows AS row
+	MATCH ()-[r]->() WHERE ID(r) = row.id` + lock + `
+	SET r += row.properties` + increment, row, nil
+}
+
+func (rqb relationshipQueryBuilder) getUnwindDelete() (string, map[string]interface{}) {
+	return `UNWIND $rows AS row
+	MATCH ()-[r]->() WHERE ID(r) = row.id
+	DELETE r`, map[string]interface{}{"id": rqb.r.getID()}
+}
+
 func (rqb relationshipQueryBuilder) getMatch(dbName string) (string, map[string]interface{}, map[string]graph) {
 	var (
 		r         = rqb.r
@@ -82,6 +179,10 @@ func (rqb relationshipQueryBuilder) getMatch(dbName string) (string, map[string]
 	return match, nil, map[string]graph{startSign: r.nodes[startNode], endSign: r.nodes[endNode]}
 }
 
+func (rqb relationshipQueryBuilder) getLock(dbName string) (string, string, map[string]interface{}, error) {
+	return getLock(rqb.r, `WHERE`, rqb.registry, dbName)
+}
+
 func (rqb relationshipQueryBuilder) getSet() (string, map[string]interface{}) {
 	var (
 		r          = rqb.r
@@ -106,39 +207,61 @@ func (rqb relationshipQueryBuilder) getSet() (string, map[string]interface{}) {
 	return set, parameters
 }
 
-func (rqb relationshipQueryBuilder) getLoadAll(IDs interface{}, lo *LoadOptions) (string, map[string]interface{}) {
+func (rqb relationshipQueryBuilder) getLoadAll(IDs interface{}, lo *LoadOptions) (string, map[string]interface{}, error) {
 
+	metadata, err := rqb.registry.get(rqb.r.getValue().Type(), lo.DatabaseName)
+	if err != nil {
+		return emptyString, nil, err
+	}
 	var (
 		depth                   = strconv.Itoa(lo.Depth)
-		metadata, _             = rqb.registry.get(rqb.r.getValue().Type(), lo.DatabaseName)
 		customIDPropertyName, _ = metadata.getCustomID(*rqb.r.getValue())
-		parameters              = map[string]interface{}{}
+		filters                 []string
 	)
 
 	if lo.Depth == infiniteDepth {
 		depth = ""
 	}
 
-	match := `MATCH path = ()-[*0..` + depth + `]-()-[r:` + rqb.r.getLabel() + `]-()-[*0..` + depth + `]-()
-	`
+	clauses, err := getLoadAllClauses("r", metadata, lo)
+	if err != nil {
+		return emptyString, nil, err
+	}
+	parameters := clauses.parameters
 
-	var filter string
 	if IDs != nil {
-		filter = `WHERE ID(r) IN $ids 
-		`
+		filter := `ID(r) IN $ids`
 		if customIDPropertyName != emptyString {
-			filter = `WHERE r.` + customIDPropertyName + ` IN $ids 
-			`
+			filter = `r.` + customIDPropertyName + ` IN $ids`
 		}
+		filters = append(filters, filter)
 		parameters["ids"] = IDs
 	}
+	filters = append(filters, clauses.filters...)
+
+	match := `MATCH ()-[r:` + rqb.r.getLabel() + `]->()
+	`
+	if len(filters) > 0 {
+		match += `WHERE ` + strings.Join(filters, ` AND `) + `
+	`
+	}
+	match += `WITH r
+	` + clauses.orderBy + clauses.pagination + `MATCH path = ()-[*0..` + depth + `]-()-[r]-()-[*0..` + depth + `]-()
+	`
 
 	end := `WITH r, path, range(0, length(path) - 1) as index
 	WITH  r, path, index, [i in index | CASE WHEN nodes(path)[i] = startNode(relationships(path)[i]) THEN false ELSE true END] as isDirectionInverted
 	RETURN path, ID(r), isDirectionInverted
-	`
+	` + clauses.orderBy
 
-	return match + filter + end, parameters
+	return match + end, parameters, nil
+}
+
+//getSoftDelete marks the relationship as deleted by setting its soft delete property instead of deleting it
+func (rqb relationshipQueryBuilder) getSoftDelete(softDeleteName string, softDeleted interface{}) (string, map[string]interface{}) {
+	return `MATCH ()-[r]->() WHERE ID(r) = $id
+	SET r.` + softDeleteName + ` = $softDeleted
+	RETURN ID(r)`, map[string]interface{}{"id": rqb.r.getID(), "softDeleted": softDeleted}
 }
 
 func (rqb relationshipQueryBuilder) getDeleteAll() (string, map[string]interface{}) {
@@ -155,6 +278,16 @@ func (rqb relationshipQueryBuilder) getDelete(dbName string) (string, map[string
 	return delete, nil, depedencies
 }
 
-func (rqb relationshipQueryBuilder) getCountEntitiesOfType() (string, map[string]interface{}) {
-	return `MATCH ()-[r:` + rqb.r.getType() + `]->() RETURN count(r) as count`, nil
+func (rqb relationshipQueryBuilder) getCountEntitiesOfType(lo *LoadOptions) (string, map[string]interface{}, error) {
+	match := `MATCH ()-[r:` + rqb.r.getType() + `]->() `
+	if !lo.IncludeSoftDeleted && rqb.r.getValue() != nil && rqb.r.getValue().IsValid() {
+		metadata, err := rqb.registry.get(rqb.r.getValue().Type(), lo.DatabaseName)
+		if err != nil {
+			return emptyString, nil, err
+		}
+		if filter, parameters := getSoftDeleteFilter("r", metadata); filter != emptyString {
+			return match + `WHERE ` + filter + ` RETURN count(r) as count`, parameters, nil
+		}
+	}
+	return match + `RETURN count(r) as count`, nil, nil
 }
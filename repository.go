@@ -0,0 +1,158 @@
+// MIT License
+//
+// Copyright (c) 2022 pmadhav
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package gogm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+//Repository provides typed access to the domain objects of type T, a struct embedding
+//Node or Relationship. It shapes the arguments of the underlying Session so that
+//a mistyped call fails at compile time instead of at runtime
+type Repository[T any] struct {
+	session Session
+}
+
+//NewRepository creates a Repository of domain objects of type T backed by session
+func NewRepository[T any](session Session) *Repository[T] {
+	return &Repository[T]{session}
+}
+
+//Session returns the session backing the repository
+func (r *Repository[T]) Session() Session {
+	return r.session
+}
+
+//FindByID loads the domain object with ID. ID is the custom ID when T has a field tagged `id`,
+//otherwise the internal ID. ErrNotFound is returned when no object has ID. A dry run returns a nil object
+func (r *Repository[T]) FindByID(ID interface{}, loadOptions *LoadOptions) (*T, error) {
+	return r.FindByIDCtx(context.Background(), ID, loadOptions)
+}
+
+func (r *Repository[T]) FindByIDCtx(ctx context.Context, ID interface{}, loadOptions *LoadOptions) (*T, error) {
+	var object *T
+	if err := r.session.LoadCtx(ctx, &object, ID, loadOptions); err != nil {
+		return nil, err
+	}
+	if object == nil && (loadOptions == nil || (loadOptions.DryRun == nil && !loadOptions.Profile.explains())) {
+		return nil, newKindError(ErrNotFound, fmt.Sprint("no domain object with ID ", ID))
+	}
+	return object, nil
+}
+
+//FindAll loads the domain objects with IDs, or all domain objects of type T when IDs is nil.
+//IDs is a slice of custom IDs or internal IDs
+func (r *Repository[T]) FindAll(IDs interface{}, loadOptions *LoadOptions) ([]*T, error) {
+	return r.FindAllCtx(context.Background(), IDs, loadOptions)
+}
+
+func (r *Repository[T]) FindAllCtx(ctx context.Context, IDs interface{}, loadOptions *LoadOptions) ([]*T, error) {
+	objects := []*T{}
+	if err := r.session.LoadAllCtx(ctx, &objects, IDs, loadOptions); err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
+
+//Query loads the domain objects returned in the first column of cypher
+func (r *Repository[T]) Query(loadOptions *LoadOptions, cypher string, parameters map[string]interface{}) ([]*T, error) {
+	return r.QueryCtx(context.Background(), loadOptions, cypher, parameters)
+}
+
+func (r *Repository[T]) QueryCtx(ctx context.Context, loadOptions *LoadOptions, cypher string, parameters map[string]interface{}) ([]*T, error) {
+	objects := []*T{}
+	if err := r.session.QueryForObjectsCtx(ctx, loadOptions, &objects, cypher, parameters); err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
+
+//Save persists object and the domain objects related to it up to the depth of saveOptions
+func (r *Repository[T]) Save(object *T, saveOptions *SaveOptions) error {
+	return r.SaveCtx(context.Background(), object, saveOptions)
+}
+
+func (r *Repository[T]) SaveCtx(ctx context.Context, object *T, saveOptions *SaveOptions) error {
+	if object == nil {
+		return errors.New("can't save a nil object")
+	}
+	return r.session.SaveCtx(ctx, &object, saveOptions)
+}
+
+//SaveAll persists objects in a single statement
+func (r *Repository[T]) SaveAll(objects []*T, saveOptions *SaveOptions) error {
+	return r.SaveAllCtx(context.Background(), objects, saveOptions)
+}
+
+func (r *Repository[T]) SaveAllCtx(ctx context.Context, objects []*T, saveOptions *SaveOptions) error {
+	if len(objects) == 0 {
+		return nil
+	}
+	return r.session.SaveCtx(ctx, &objects, saveOptions)
+}
+
+//Delete deletes object from the database
+func (r *Repository[T]) Delete(object *T, deleteOptions *DeleteOptions) error {
+	return r.DeleteCtx(context.Background(), object, deleteOptions)
+}
+
+func (r *Repository[T]) DeleteCtx(ctx context.Context, object *T, deleteOptions *DeleteOptions) error {
+	if object == nil {
+		return errors.New("can't delete a nil object")
+	}
+	return r.session.DeleteCtx(ctx, &object, deleteOptions)
+}
+
+//HardDelete deletes object from the database, even when T has a soft delete field
+func (r *Repository[T]) HardDelete(object *T, deleteOptions *DeleteOptions) error {
+	return r.HardDeleteCtx(context.Background(), object, deleteOptions)
+}
+
+func (r *Repository[T]) HardDeleteCtx(ctx context.Context, object *T, deleteOptions *DeleteOptions) error {
+	if object == nil {
+		return errors.New("can't delete a nil object")
+	}
+	return r.session.HardDeleteCtx(ctx, &object, deleteOptions)
+}
+
+//DeleteAll deletes all domain objects of type T from the database
+func (r *Repository[T]) DeleteAll(deleteOptions *DeleteOptions) error {
+	return r.DeleteAllCtx(context.Background(), deleteOptions)
+}
+
+func (r *Repository[T]) DeleteAllCtx(ctx context.Context, deleteOptions *DeleteOptions) error {
+	ref := new(T)
+	return r.session.DeleteAllCtx(ctx, &ref, deleteOptions)
+}
+
+//Count returns the number of domain objects of type T in the database
+func (r *Repository[T]) Count(loadOptions *LoadOptions) (int64, error) {
+	return r.CountCtx(context.Background(), loadOptions)
+}
+
+func (r *Repository[T]) CountCtx(ctx context.Context, loadOptions *LoadOptions) (int64, error) {
+	ref := new(T)
+	return r.session.CountEntitiesOfTypeCtx(ctx, loadOptions, &ref)
+}
@@ -23,9 +23,12 @@
 package gogm
 
 import (
+	"context"
 	"errors"
 	"reflect"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
 )
@@ -33,39 +36,111 @@ import (
 type saver struct {
 	cypherExecuter *cypherExecuter
 	store          store
-	eventer        eventer
+	eventer        *eventer
 	registry       *registry
 	graphFactory   graphFactory
+
+	//kept are the domain fields set by the save being persisted, restored when persisting it fails
+	kept domainFields
 }
 
-func newSaver(cypherExecuter *cypherExecuter, store store, eventer eventer, registry *registry, graphFactory graphFactory) *saver {
-	return &saver{cypherExecuter, store, eventer, registry, graphFactory}
+func newSaver(cypherExecuter *cypherExecuter, store store, eventer *eventer, registry *registry, graphFactory graphFactory) *saver {
+	return &saver{cypherExecuter: cypherExecuter, store: store, eventer: eventer, registry: registry, graphFactory: graphFactory}
 }
 
-func (s *saver) save(object interface{}, saveOptions *SaveOptions) error {
+func (s *saver) save(ctx context.Context, object interface{}, saveOptions *SaveOptions) error {
 	var (
-		graphs        []graph
-		record        *neo4j.Record
-		savedGraphs   map[string]graph
-		deletedGraphs map[string]graph
-		err           error
-		store         = s.store
-		savedDepths   []int
+		graphs []graph
+		err    error
 	)
 
+	if saveOptions, err = checkSaveOptions(saveOptions); err != nil {
+		return err
+	}
+
+	if graphs, err = s.graphFactory.get(reflect.ValueOf(object), nil, saveOptions.DatabaseName); err != nil {
+		return err
+	}
+
+	return s.saveGraphs(ctx, graphs, saveOptions)
+}
+
+//flush saves every domain object managed by the session, that is loaded or saved in it, as a root of
+//saveOptions.Depth. As with save, only the changes are written and they are written in one transaction
+func (s *saver) flush(ctx context.Context, saveOptions *SaveOptions) error {
+	var (
+		graphs       []graph
+		objectGraphs []graph
+		managed      []graph
+		err          error
+	)
+
+	if saveOptions, err = checkSaveOptions(saveOptions); err != nil {
+		return err
+	}
+
+	for _, g := range s.store.all() {
+		if g.getValue() != nil && g.getValue().IsValid() {
+			managed = append(managed, g)
+		}
+	}
+	//Nodes first, in the order they were created, for a deterministic statement
+	sort.SliceStable(managed, func(i, j int) bool {
+		iIsNode, jIsNode := reflect.TypeOf(managed[i]) == typeOfPrivateNode, reflect.TypeOf(managed[j]) == typeOfPrivateNode
+		if iIsNode != jIsNode {
+			return iIsNode
+		}
+		return managed[i].getID() < managed[j].getID()
+	})
+
+	for _, g := range managed {
+		object := reflect.New(g.getValue().Type())
+		object.Elem().Set(*g.getValue())
+		if objectGraphs, err = s.graphFactory.get(object, nil, saveOptions.DatabaseName); err != nil {
+			return err
+		}
+		graphs = append(graphs, objectGraphs...)
+	}
+
+	if len(graphs) == 0 {
+		return nil
+	}
+
+	return s.saveGraphs(ctx, graphs, saveOptions)
+}
+
+func checkSaveOptions(saveOptions *SaveOptions) (*SaveOptions, error) {
 	if saveOptions == nil {
 		saveOptions = NewSaveOptions("", maxDepth)
 	}
 
 	if saveOptions.Depth > maxDepth {
-		return errors.New("cannot save greater than max depth")
+		return nil, errors.New("cannot save greater than max depth")
 	}
 
-	if graphs, err = s.graphFactory.get(reflect.ValueOf(object), nil, saveOptions.DatabaseName); err != nil {
-		return err
+	if saveOptions.BatchSize < 0 {
+		return nil, errors.New("BatchSize of save options can't be negative")
 	}
+	return saveOptions, nil
+}
+
+func (s *saver) saveGraphs(ctx context.Context, graphs []graph, saveOptions *SaveOptions) error {
+	var (
+		record        *neo4j.Record
+		savedGraphs   map[string]graph
+		deletedGraphs map[string]graph
+		err           error
+		store         = s.store
+		savedDepths   []int
+	)
 
-	if savedDepths, record, savedGraphs, deletedGraphs, err = s.persist(graphs, saveOptions); err != nil {
+	s.kept = domainFields{}
+	savedDepths, record, savedGraphs, deletedGraphs, err = s.persist(ctx, graphs, saveOptions)
+	kept := s.kept
+	s.kept = nil
+	if err != nil {
+		//The timestamps set on domain objects weren't written
+		kept.restore()
 		return err
 	}
 
@@ -81,6 +156,13 @@ func (s *saver) save(object interface{}, saveOptions *SaveOptions) error {
 				createdGraphSignatures[savedGraphs[key].getSignature()] = true
 			}
 
+			if version, isVersioned := properties[versionPropertyName].(int64); isVersioned && savedGraphs[key] != nil {
+				s.keepDomainFields(savedGraphs[key], saveOptions.DatabaseName)
+				if err = setGraphVersion(savedGraphs[key], s.registry, saveOptions.DatabaseName, version); err != nil {
+					return err
+				}
+			}
+
 			if deletedGraphs[key] != nil {
 				//deletedGraphs[key] has been deleted. Update the local store and notify objects
 				for _, relatedGraph := range deletedGraphs[key].getRelatedGraphs() {
@@ -115,9 +197,10 @@ func (s *saver) save(object interface{}, saveOptions *SaveOptions) error {
 					}
 					store.save(g, saveOptions.DatabaseName)
 					if g.getValue().IsValid() {
-						for _, eventListener := range s.eventer.eventListeners {
-							eventListener.OnPostSave(event{g.getValue(), saveLifecycle})
-						}
+						savedValue := g.getValue()
+						s.eventer.post(func(eventListener EventListener) {
+							eventListener.OnPostSave(event{savedValue, saveLifecycle})
+						})
 					}
 				}
 			}
@@ -127,7 +210,7 @@ func (s *saver) save(object interface{}, saveOptions *SaveOptions) error {
 	return err
 }
 
-func (s *saver) persist(graphs []graph, saveOptions *SaveOptions) ([]int, *neo4j.Record, map[string]graph, map[string]graph, error) {
+func (s *saver) persist(ctx context.Context, graphs []graph, saveOptions *SaveOptions) ([]int, *neo4j.Record, map[string]graph, map[string]graph, error) {
 
 	var (
 		err    error
@@ -148,6 +231,21 @@ func (s *saver) persist(graphs []graph, saveOptions *SaveOptions) ([]int, *neo4j
 		dbName             string = ""
 	)
 
+	if isDryRun(ctx) {
+		//Building the statements sets IDs, timestamps and versions of domain objects and graphs of the store
+		kept := domainObjects{}
+		snapshot := s.store.snapshot()
+		defer func() {
+			s.store.restore(snapshot)
+			kept.restore()
+		}()
+		ider := ensureID
+		ensureID = func(g graph) {
+			kept.keep(g)
+			ider(g)
+		}
+	}
+
 	for index, graph := range graphs {
 		ensureID(graph)
 		for _, rg := range graph.getRelatedGraphs() {
@@ -178,6 +276,13 @@ func (s *saver) persist(graphs []graph, saveOptions *SaveOptions) ([]int, *neo4j
 		}
 	}
 
+	if saveOptions != nil && saveOptions.BatchSize > 0 {
+		if record, err = s.persistBatched(ctx, grandSavedGraphs, grandDeletedGraphs, saveOptions); err != nil {
+			return savedDepths, nil, nil, nil, err
+		}
+		return savedDepths, record, grandSavedGraphs, grandDeletedGraphs, nil
+	}
+
 	var grandSaveClauses = make(clauses)
 	for _, graphSaveClauses := range saveClausesSlice {
 		for clause, grandSaveClause := range graphSaveClauses {
@@ -185,18 +290,33 @@ func (s *saver) persist(graphs []graph, saveOptions *SaveOptions) ([]int, *neo4j
 		}
 	}
 
+	if saveOptions != nil {
+		dbName = saveOptions.DatabaseName
+	}
+
 	cypher := getCyhperFromClauses(grandSaveClauses)
 	graphGroups := [2]map[string]graph{grandSavedGraphs, grandDeletedGraphs}
 	_return := ``
-	for _, graphGroup := range graphGroups {
+	isVersioned := false
+	for index, graphGroup := range graphGroups {
 		if len(graphGroup) > 0 {
 			begin := `, `
 			if _return == emptyString {
 				begin = `return `
 			}
 			_return += begin
-			for entityCQLRef := range graphGroup {
-				_return += entityCQLRef + `{` + idPropertyName + `:ID(` + entityCQLRef + `)},`
+			for entityCQLRef, g := range graphGroup {
+				version := emptyString
+				versionName, _, err := getGraphVersion(g, s.registry, dbName)
+				if err != nil {
+					return savedDepths, nil, nil, nil, err
+				}
+				if versionName != emptyString && index == 0 {
+					//Return the version of versioned graphs so that domain objects get incremented versions
+					version = `, ` + versionPropertyName + `:` + entityCQLRef + `.` + versionName
+					isVersioned = true
+				}
+				_return += entityCQLRef + `{` + idPropertyName + `:ID(` + entityCQLRef + `)` + version + `},`
 			}
 			_return = strings.TrimSuffix(_return, ",")
 		}
@@ -206,18 +326,215 @@ func (s *saver) persist(graphs []graph, saveOptions *SaveOptions) ([]int, *neo4j
 
 	if cypher != emptyString {
 		var records []*neo4j.Record
-		if saveOptions != nil {
-			dbName = saveOptions.DatabaseName
-		}
-		if records, err = s.cypherExecuter.collect(dbName, cypher, grandParams); err != nil {
+		if records, err = s.cypherExecuter.collect(ctx, dbName, cypher, grandParams); err != nil {
 			return savedDepths, nil, nil, nil, err
 		}
+		if isDryRun(ctx) {
+			return savedDepths, nil, nil, nil, nil
+		}
+		if len(records) == 0 {
+			//A graph to update wasn't matched
+			if isVersioned {
+				return savedDepths, nil, nil, nil, ErrOptimisticLock
+			}
+			return savedDepths, nil, nil, nil, newKindError(ErrNotFound, "Can't save, an object to update no longer exists in the database")
+		}
 		record = records[0]
 	}
 
 	return savedDepths, record, grandSavedGraphs, grandDeletedGraphs, err
 }
 
+//Batched writes happen in this order so that relationships are created after their endpoints
+const (
+	nodeCreateBatch = iota
+	nodeSetBatch
+	relationshipCreateBatch
+	relationshipSetBatch
+	relationshipDeleteBatch
+	batchPhases
+)
+
+//persistBatched writes the graphs collected by getSaveMeta with UNWIND statements instead of a
+//single statement with a clause per graph. Graphs written by the same statement, such as the new
+//nodes of a label, are sent as rows of at most saveOptions.BatchSize. All statements run in one
+//transaction and the returned record has the shape of the record returned by persist
+func (s *saver) persistBatched(ctx context.Context, savedGraphs map[string]graph, deletedGraphs map[string]graph, saveOptions *SaveOptions) (*neo4j.Record, error) {
+	var (
+		err        error
+		cBuilder   graphQueryBuilder
+		cypher     string
+		row        map[string]interface{}
+		batches    [batchPhases]map[string][]map[string]interface{}
+		createdIDs map[int64]int64
+		versions   [batchPhases]map[int64]int64
+		hasRows    bool
+		dbName     = saveOptions.DatabaseName
+	)
+
+	for phase := range batches {
+		batches[phase] = map[string][]map[string]interface{}{}
+	}
+
+	addRow := func(phase int, cypher string, row map[string]interface{}) {
+		batches[phase][cypher] = append(batches[phase][cypher], row)
+		hasRows = true
+	}
+
+	for _, g := range savedGraphs {
+		if cBuilder, err = newCypherBuilder(g, s.registry, s.store, dbName); err != nil {
+			return nil, err
+		}
+		if !cBuilder.isGraphDirty() {
+			continue
+		}
+		phase := nodeCreateBatch
+		if reflect.TypeOf(g) == typeOfPrivateRelationship {
+			phase = relationshipCreateBatch
+		}
+		if g.getID() < 0 {
+			if cypher, row, err = cBuilder.getUnwindCreate(saveOptions.Merge, dbName); err != nil {
+				return nil, err
+			}
+		} else {
+			phase++
+			if cypher, row, err = cBuilder.getUnwindSet(dbName); err != nil {
+				return nil, err
+			}
+			if cypher == emptyString {
+				continue
+			}
+		}
+		addRow(phase, cypher, row)
+	}
+
+	for _, g := range deletedGraphs {
+		if cBuilder, err = newCypherBuilder(g, s.registry, nil, dbName); err != nil {
+			return nil, err
+		}
+		cypher, row = cBuilder.getUnwindDelete()
+		addRow(relationshipDeleteBatch, cypher, row)
+	}
+
+	if !hasRows {
+		return nil, nil
+	}
+
+	work := func(run statementRunner) error {
+		createdIDs = map[int64]int64{}
+		for phase, batch := range batches {
+			versions[phase] = map[int64]int64{}
+			cyphers := make([]string, 0, len(batch))
+			for cypher := range batch {
+				cyphers = append(cyphers, cypher)
+			}
+			sort.Strings(cyphers)
+
+			for _, cypher := range cyphers {
+				rows := batch[cypher]
+				for begin := 0; begin < len(rows); begin += saveOptions.BatchSize {
+					end := begin + saveOptions.BatchSize
+					if end > len(rows) {
+						end = len(rows)
+					}
+					chunk := make([]map[string]interface{}, 0, end-begin)
+					for _, row := range rows[begin:end] {
+						//A dry run creates nothing, the temporary IDs are left in the rows
+						if isDryRun(ctx) {
+							chunk = append(chunk, row)
+							continue
+						}
+						resolvedRow, err := resolveBatchRow(row, createdIDs)
+						if err != nil {
+							return err
+						}
+						chunk = append(chunk, resolvedRow)
+					}
+					records, err := run(cypher, map[string]interface{}{"rows": chunk})
+					if err != nil {
+						return err
+					}
+					//A dry run returns no records
+					if isDryRun(ctx) {
+						continue
+					}
+					if phase == nodeCreateBatch || phase == relationshipCreateBatch {
+						for _, record := range records {
+							createdIDs[record.Values[0].(int64)] = record.Values[1].(int64)
+						}
+					} else if _, isVersioned := chunk[0]["version"]; isVersioned {
+						if len(records) != len(chunk) {
+							return ErrOptimisticLock
+						}
+						for _, record := range records {
+							versions[phase][record.Values[0].(int64)] = record.Values[1].(int64)
+						}
+					}
+				}
+			}
+		}
+		return nil
+	}
+
+	if err = s.cypherExecuter.execWork(ctx, dbName, work); err != nil || isDryRun(ctx) {
+		return nil, err
+	}
+
+	record := &neo4j.Record{}
+	for _, graphGroup := range [2]map[string]graph{savedGraphs, deletedGraphs} {
+		for entityCQLRef, g := range graphGroup {
+			ID := g.getID()
+			if ID < 0 {
+				var created bool
+				if ID, created = createdIDs[ID]; !created {
+					continue
+				}
+			}
+			properties := map[string]interface{}{idPropertyName: ID}
+			setPhase := nodeSetBatch
+			if reflect.TypeOf(g) == typeOfPrivateRelationship {
+				setPhase = relationshipSetBatch
+			}
+			if version, updated := versions[setPhase][ID]; updated {
+				properties[versionPropertyName] = version
+			}
+			record.Keys = append(record.Keys, entityCQLRef)
+			record.Values = append(record.Values, properties)
+		}
+	}
+	return record, nil
+}
+
+//resolveBatchRow copies row, replacing the temporary IDs of the endpoints of a new relationship
+//with the IDs the database generated for them earlier in the transaction
+func resolveBatchRow(row map[string]interface{}, createdIDs map[int64]int64) (map[string]interface{}, error) {
+	resolvedRow := make(map[string]interface{}, len(row))
+	for key, value := range row {
+		if key == "start" || key == "end" {
+			if ID := value.(int64); ID < 0 {
+				createdID, created := createdIDs[ID]
+				if !created {
+					return nil, errors.New("Can't save relationship, its " + key + " node wasn't saved")
+				}
+				value = createdID
+			}
+		}
+		resolvedRow[key] = value
+	}
+	return resolvedRow, nil
+}
+
+//keepDomainFields keeps the version and the timestamps of the domain object of g before they're set, so that a
+//failed save and rolling back the transaction, if any, restore them
+func (s *saver) keepDomainFields(g graph, dbName string) {
+	if s.kept != nil {
+		s.kept.keep(g, s.registry, dbName)
+	}
+	if transaction := s.cypherExecuter.transaction; transaction != nil && transaction.snapshot != nil {
+		transaction.snapshot.domainFields.keep(g, s.registry, dbName)
+	}
+}
+
 func (s *saver) getSaveMeta(g graph, saveOptions *SaveOptions, ensureID func(graph), loadedGraphs store) (int, map[clause][]string, map[string]graph, map[string]graph, map[string]interface{}, error) {
 	var (
 		err error
@@ -231,6 +548,8 @@ func (s *saver) getSaveMeta(g graph, saveOptions *SaveOptions, ensureID func(gra
 		savedDepth  = -1
 		depedencies []map[string]graph
 		dbName      string = ""
+
+		now = time.Now()
 	)
 
 	if saveOptions != nil {
@@ -270,8 +589,25 @@ func (s *saver) getSaveMeta(g graph, saveOptions *SaveOptions, ensureID func(gra
 		}
 		if cBuilder.isGraphDirty() {
 
+			//Timestamps are part of the changes to write. Build the changes again to include them
+			s.keepDomainFields(queue[0], dbName)
+			var isTimestamped bool
+			if isTimestamped, err = setTimestamps(queue[0], s.registry, dbName, now); err != nil {
+				return savedDepth, nil, nil, nil, nil, err
+			}
+			if isTimestamped {
+				if cBuilder, err = newCypherBuilder(queue[0], s.registry, s.store, dbName); err != nil {
+					return savedDepth, nil, nil, nil, nil, err
+				}
+			}
+
 			if queue[0].getID() < 0 {
 				nodeCreate, relationshipCreate, createParameters, createDeps := cBuilder.getCreate()
+				if saveOptions.Merge {
+					if nodeCreate, relationshipCreate, createParameters, createDeps, err = cBuilder.getMerge(dbName); err != nil {
+						return savedDepth, nil, nil, nil, nil, err
+					}
+				}
 				parameters = append(parameters, createParameters)
 				if nodeCreate != emptyString {
 					graphSaveClauses[nodeCreateClause] = append(graphSaveClauses[nodeCreateClause], nodeCreate)
@@ -283,10 +619,17 @@ func (s *saver) getSaveMeta(g graph, saveOptions *SaveOptions, ensureID func(gra
 				depedencies = append(depedencies, createDeps)
 			} else {
 				match, matchParameters, matchDeps := cBuilder.getMatch(dbName)
-				parameters = append(parameters, matchParameters)
-				graphSaveClauses[matchClause] = append(graphSaveClauses[matchClause], match)
+				lock, increment, lockParameters, err := cBuilder.getLock(dbName)
+				if err != nil {
+					return -1, nil, nil, nil, nil, err
+				}
+				parameters = append(parameters, matchParameters, lockParameters)
+				graphSaveClauses[matchClause] = append(graphSaveClauses[matchClause], match+lock)
 
 				depedencies = append(depedencies, matchDeps)
+				if increment != emptyString {
+					graphSaveClauses[setClause] = append(graphSaveClauses[setClause], increment)
+				}
 			}
 			set, setParameters := cBuilder.getSet()
 			parameters = append(parameters, setParameters)
@@ -0,0 +1,323 @@
+// MIT License
+//
+// Copyright (c) 2022 pmadhav
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package gogm
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+//SchemaDialect is the syntax of the statements creating constraints and indexes
+type SchemaDialect int
+
+const (
+	//DetectSchemaDialect detects the dialect from the version of the server
+	DetectSchemaDialect SchemaDialect = iota
+
+	//Neo4j4SchemaDialect is the syntax of Neo4j 4, e.g. CREATE CONSTRAINT ... ON ... ASSERT
+	Neo4j4SchemaDialect
+
+	//Neo4j5SchemaDialect is the syntax of Neo4j 5 and later, e.g. CREATE CONSTRAINT ... FOR ... REQUIRE
+	Neo4j5SchemaDialect
+)
+
+const kernelComponent = "Neo4j Kernel"
+
+//detectSchemaDialect returns the dialect of the server version reported by dbms.components()
+func detectSchemaDialect(ctx context.Context, cypherExecuter *cypherExecuter, dbName string) (SchemaDialect, error) {
+	records, err := cypherExecuter.collect(ctx, dbName, `CALL dbms.components() YIELD name, versions WHERE name = $name RETURN versions[0]`, map[string]interface{}{"name": kernelComponent})
+	if err != nil {
+		return DetectSchemaDialect, err
+	}
+	if len(records) == 0 {
+		return Neo4j4SchemaDialect, nil
+	}
+	version, _ := records[0].Values[0].(string)
+	return getSchemaDialect(version), nil
+}
+
+//getSchemaDialect returns the dialect of version. Versions after 5 are calendar versions, e.g. 2025.01.0
+func getSchemaDialect(version string) SchemaDialect {
+	major, err := strconv.Atoi(strings.SplitN(version, ".", 2)[0])
+	if err != nil || major < 5 {
+		return Neo4j4SchemaDialect
+	}
+	return Neo4j5SchemaDialect
+}
+
+//getSchemaName names a schema object of kind on label, e.g. index_Movie_title_released
+func getSchemaName(kind string, label string, parts []string) string {
+	name := strings.Join(append([]string{kind, label}, parts...), "_")
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+//schema are the constraints and indexes declared by the tags of a domain object. Properties are in the order
+//of their names and groups map the value of a tag to its properties
+type schema struct {
+	unique    []string
+	exists    []string
+	texts     []string
+	nodeKeys  map[string][]string
+	indexes   map[string][]string
+	fullTexts map[string][]string
+}
+
+func getSchema(metadata metadata, isNode bool) *schema {
+	var (
+		names        []string
+		structFields = metadata.getPropertyStructFields()
+		s            = &schema{nodeKeys: map[string][]string{}, indexes: map[string][]string{}, fullTexts: map[string][]string{}}
+	)
+	for name := range structFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	isUnique := map[string]bool{}
+	for _, name := range names {
+		namespaceTag := getNamespacedTag(structFields[name].Tag)
+		//Relationship uniqueness constraints aren't supported by Neo4j 4
+		if isNode && (len(namespaceTag.get(uniqueTag)) > 0 || len(namespaceTag.get(customIDTag)) > 0) {
+			s.unique = append(s.unique, name)
+			isUnique[name] = true
+		}
+		if len(namespaceTag.get(existsTag)) > 0 {
+			s.exists = append(s.exists, name)
+		}
+		if len(namespaceTag.get(textTag)) > 0 {
+			s.texts = append(s.texts, name)
+		}
+		if isNode {
+			for _, group := range namespaceTag.get(nodeKeyTag) {
+				s.nodeKeys[group] = append(s.nodeKeys[group], name)
+			}
+		}
+		for _, group := range namespaceTag.get(fullTextTag) {
+			s.fullTexts[group] = append(s.fullTexts[group], name)
+		}
+		for _, group := range namespaceTag.get(indexTag) {
+			//Unique properties are already indexed
+			if group != emptyString || !isUnique[name] {
+				s.indexes[group] = append(s.indexes[group], name)
+			}
+		}
+	}
+	return s
+}
+
+//SchemaKind is the kind of a constraint or an index
+type SchemaKind string
+
+const (
+	//UniqueConstraint is a property uniqueness constraint
+	UniqueConstraint SchemaKind = "UNIQUENESS"
+
+	//NodeKeyConstraint is a node key constraint
+	NodeKeyConstraint SchemaKind = "NODE_KEY"
+
+	//ExistenceConstraint is a property existence constraint
+	ExistenceConstraint SchemaKind = "PROPERTY_EXISTENCE"
+
+	//RangeIndex is a range index, reported as a BTREE index by Neo4j 4
+	RangeIndex SchemaKind = "RANGE"
+
+	//TextIndex is a text index
+	TextIndex SchemaKind = "TEXT"
+
+	//FullTextIndex is a full-text index
+	FullTextIndex SchemaKind = "FULLTEXT"
+)
+
+const (
+	nodeEntityType         = "NODE"
+	relationshipEntityType = "RELATIONSHIP"
+)
+
+//SchemaItem is a constraint or an index
+type SchemaItem struct {
+	//Name is empty for the declared constraints and indexes which are named by the server
+	Name string
+	Kind SchemaKind
+
+	//EntityType is NODE or RELATIONSHIP
+	EntityType string
+
+	//Labels are the labels of the nodes or the type of the relationships
+	Labels     []string
+	Properties []string
+
+	constraint bool
+}
+
+//IsConstraint returns true when the item is a constraint, false when it's an index
+func (i SchemaItem) IsConstraint() bool {
+	return i.constraint
+}
+
+//getSchemaItems returns the constraints and indexes declared by the tags of the domain object of metadata
+func getSchemaItems(metadata metadata, dialect SchemaDialect) []SchemaItem {
+	var (
+		items      []SchemaItem
+		labels     []string
+		isNode     = reflect.TypeOf(metadata) == typeOfNodeMetadata
+		entityType = nodeEntityType
+	)
+
+	if isNode {
+		labels = metadata.(*nodeMetadata).thisStructLabel
+	} else if metadata.getStructLabel() != emptyString {
+		labels = []string{metadata.getStructLabel()}
+		entityType = relationshipEntityType
+	}
+
+	s := getSchema(metadata, isNode)
+	for _, label := range labels {
+		item := func(kind SchemaKind, name string, properties []string) SchemaItem {
+			return SchemaItem{
+				Name:       name,
+				Kind:       kind,
+				EntityType: entityType,
+				Labels:     []string{label},
+				Properties: properties,
+				constraint: kind == UniqueConstraint || kind == NodeKeyConstraint || kind == ExistenceConstraint}
+		}
+
+		for _, name := range s.unique {
+			items = append(items, item(UniqueConstraint, emptyString, []string{name}))
+		}
+		for _, group := range sortedGroups(s.nodeKeys) {
+			items = append(items, item(NodeKeyConstraint, emptyString, s.nodeKeys[group]))
+		}
+		for _, name := range s.exists {
+			items = append(items, item(ExistenceConstraint, emptyString, []string{name}))
+		}
+		for _, group := range sortedGroups(s.indexes) {
+			names := s.indexes[group]
+			//Neo4j 4 names the composite index of the properties tagged index without a value
+			if group == emptyString && isNode && dialect != Neo4j5SchemaDialect {
+				items = append(items, item(RangeIndex, emptyString, names))
+				continue
+			}
+			items = append(items, item(RangeIndex, getSchemaName("index", label, groupName(group, names)), names))
+		}
+		for _, name := range s.texts {
+			items = append(items, item(TextIndex, getSchemaName("text", label, []string{name}), []string{name}))
+		}
+		for _, group := range sortedGroups(s.fullTexts) {
+			items = append(items, item(FullTextIndex, getSchemaName("fulltext", label, groupName(group, s.fullTexts[group])), s.fullTexts[group]))
+		}
+	}
+	return items
+}
+
+//getCreateSchemaStatement returns the statements creating the constraints and indexes declared by the tags of the
+//domain object of metadata. Statements don't fail when their constraint or index exists
+func getCreateSchemaStatement(metadata metadata, dialect SchemaDialect) []string {
+	var statements []string
+	for _, item := range getSchemaItems(metadata, dialect) {
+		statements = append(statements, getCreateSchemaItemStatement(item, dialect))
+	}
+	return statements
+}
+
+//getCreateSchemaItemStatement returns the statement creating item unless it exists
+func getCreateSchemaItemStatement(item SchemaItem, dialect SchemaDialect) string {
+	variable, pattern := `n`, `(n:`+strings.Join(item.Labels, `|`)+`)`
+	if item.EntityType == relationshipEntityType {
+		variable, pattern = `r`, `()-[r:`+strings.Join(item.Labels, `|`)+`]-()`
+	}
+	var properties []string
+	for _, name := range item.Properties {
+		properties = append(properties, variable+`.`+name)
+	}
+	name := emptyString
+	if item.Name != emptyString {
+		name = quoteSchemaName(item.Name) + spaceString
+	}
+	constraint := func(requirement string) string {
+		if dialect == Neo4j5SchemaDialect {
+			return `CREATE CONSTRAINT ` + name + `IF NOT EXISTS FOR ` + pattern + ` REQUIRE ` + requirement
+		}
+		return `CREATE CONSTRAINT ` + name + `IF NOT EXISTS ON ` + pattern + ` ASSERT ` + requirement
+	}
+	requirement := strings.Join(properties, `, `)
+	if len(properties) > 1 {
+		requirement = `(` + requirement + `)`
+	}
+
+	switch item.Kind {
+	case UniqueConstraint:
+		return constraint(requirement + ` IS UNIQUE`)
+	case NodeKeyConstraint:
+		return constraint(`(` + strings.Join(properties, `, `) + `) IS NODE KEY`)
+	case ExistenceConstraint:
+		return constraint(requirement + ` IS NOT NULL`)
+	case TextIndex:
+		return `CREATE TEXT INDEX ` + name + `IF NOT EXISTS FOR ` + pattern + ` ON (` + strings.Join(properties, `, `) + `)`
+	case FullTextIndex:
+		return `CREATE FULLTEXT INDEX ` + name + `IF NOT EXISTS FOR ` + pattern + ` ON EACH [` + strings.Join(properties, `, `) + `]`
+	}
+	if name == emptyString && item.EntityType == nodeEntityType && dialect != Neo4j5SchemaDialect {
+		return `CREATE INDEX ON :` + item.Labels[0] + `(` + strings.Join(item.Properties, indexDelim) + `)`
+	}
+	return `CREATE INDEX ` + name + `IF NOT EXISTS FOR ` + pattern + ` ON (` + strings.Join(properties, `, `) + `)`
+}
+
+//getDropSchemaItemStatement returns the statement dropping item, an item of the database, unless it doesn't exist
+func getDropSchemaItemStatement(item SchemaItem) string {
+	if item.constraint {
+		return `DROP CONSTRAINT ` + quoteSchemaName(item.Name) + ` IF EXISTS`
+	}
+	return `DROP INDEX ` + quoteSchemaName(item.Name) + ` IF EXISTS`
+}
+
+//quoteSchemaName quotes the name of a constraint or an index, which may be named outside of the OGM
+func quoteSchemaName(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+func sortedGroups(groups map[string][]string) []string {
+	var names []string
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+//groupName returns the parts of the name of a schema object of group. An unnamed group is named after its properties
+func groupName(group string, properties []string) []string {
+	if group == emptyString {
+		return properties
+	}
+	return []string{group}
+}
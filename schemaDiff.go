@@ -0,0 +1,206 @@
+// MIT License
+//
+// Copyright (c) 2022 pmadhav
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package gogm
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+)
+
+//SchemaReport is the difference between the constraints and indexes declared by the registered domain objects
+//and those of the database
+type SchemaReport struct {
+	//Missing are declared but not in the database
+	Missing []SchemaItem
+
+	//Extra are in the database but not declared, e.g. left over by a removed tag or created outside of the OGM
+	Extra []SchemaItem
+
+	//Mismatched are declared and in the database under the same name but with different definitions
+	Mismatched []SchemaMismatch
+}
+
+//SchemaMismatch is a declared constraint or index and the one of the database with the same name
+type SchemaMismatch struct {
+	Declared SchemaItem
+	Actual   SchemaItem
+}
+
+//SchemaApplyOptions represents options used for applying the declared schema to the database.
+//Missing constraints and indexes are always created
+type SchemaApplyOptions struct {
+	//DropExtra drops the constraints and indexes which aren't declared
+	DropExtra bool
+
+	//ReplaceMismatched drops the mismatched constraints and indexes and creates the declared ones
+	ReplaceMismatched bool
+}
+
+//IsEmpty returns true when the schema of the database is in sync with the declared schema
+func (r SchemaReport) IsEmpty() bool {
+	return len(r.Missing) == 0 && len(r.Extra) == 0 && len(r.Mismatched) == 0
+}
+
+//schemaKinds maps the constraint and index types reported by the server to the kinds of the OGM
+var schemaKinds = map[string]SchemaKind{
+	"UNIQUENESS":                       UniqueConstraint,
+	"NODE_PROPERTY_UNIQUENESS":         UniqueConstraint,
+	"RELATIONSHIP_UNIQUENESS":          UniqueConstraint,
+	"RELATIONSHIP_PROPERTY_UNIQUENESS": UniqueConstraint,
+	"NODE_KEY":                         NodeKeyConstraint,
+	"NODE_PROPERTY_EXISTENCE":          ExistenceConstraint,
+	"RELATIONSHIP_PROPERTY_EXISTENCE":  ExistenceConstraint,
+	"BTREE":                            RangeIndex,
+	"RANGE":                            RangeIndex,
+	"TEXT":                             TextIndex,
+	"FULLTEXT":                         FullTextIndex}
+
+//getDeclaredSchema returns the constraints and indexes declared by the domain objects of metadatas
+func getDeclaredSchema(metadatas []metadata, dialect SchemaDialect) []SchemaItem {
+	var (
+		items    []SchemaItem
+		declared = map[string]bool{}
+	)
+	for _, metadata := range metadatas {
+		for _, item := range getSchemaItems(metadata, dialect) {
+			//Domain objects sharing a label declare the same items
+			if key := item.Name + spaceString + item.definition(); !declared[key] {
+				declared[key] = true
+				items = append(items, item)
+			}
+		}
+	}
+	return items
+}
+
+//getDatabaseSchema returns the constraints and indexes of the database. Indexes backing a constraint are part
+//of the constraint and token lookup indexes are managed by the server, hence they're left out
+func getDatabaseSchema(ctx context.Context, cypherExecuter *cypherExecuter, dbName string) ([]SchemaItem, error) {
+	var items []SchemaItem
+
+	records, err := cypherExecuter.collect(ctx, dbName, `SHOW CONSTRAINTS`, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		items = append(items, getDatabaseSchemaItem(record, true))
+	}
+
+	if records, err = cypherExecuter.collect(ctx, dbName, `SHOW INDEXES`, nil); err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		uniqueness, _ := record.Get("uniqueness")
+		owningConstraint, _ := record.Get("owningConstraint")
+		indexType, _ := record.Get("type")
+		if uniqueness == "UNIQUE" || owningConstraint != nil || indexType == "LOOKUP" {
+			continue
+		}
+		items = append(items, getDatabaseSchemaItem(record, false))
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Name < items[j].Name
+	})
+	return items, nil
+}
+
+func getDatabaseSchemaItem(record *neo4j.Record, constraint bool) SchemaItem {
+	item := SchemaItem{constraint: constraint}
+
+	name, _ := record.Get("name")
+	item.Name, _ = name.(string)
+	entityType, _ := record.Get("entityType")
+	item.EntityType, _ = entityType.(string)
+	schemaType, _ := record.Get("type")
+	typeName, _ := schemaType.(string)
+	if kind, isKnown := schemaKinds[typeName]; isKnown {
+		item.Kind = kind
+	} else {
+		item.Kind = SchemaKind(typeName)
+	}
+	labels, _ := record.Get("labelsOrTypes")
+	item.Labels = toStrings(labels)
+	properties, _ := record.Get("properties")
+	item.Properties = toStrings(properties)
+	return item
+}
+
+func toStrings(values interface{}) []string {
+	var strs []string
+	list, _ := values.([]interface{})
+	for _, value := range list {
+		if str, isString := value.(string); isString {
+			strs = append(strs, str)
+		}
+	}
+	return strs
+}
+
+//definition identifies item by what it constrains or indexes, regardless of its name
+func (i SchemaItem) definition() string {
+	return strings.Join([]string{string(i.Kind), i.EntityType, strings.Join(i.Labels, labelsDelim), strings.Join(i.Properties, indexDelim)}, spaceString)
+}
+
+//diffSchema compares the declared constraints and indexes with the actual ones, those of the database. A declared
+//item is in the database when an item has the same definition, whatever its name. Otherwise it's mismatched when
+//an item has the same name and missing when none has
+func diffSchema(declared []SchemaItem, actual []SchemaItem) SchemaReport {
+	var (
+		report       SchemaReport
+		definitions  = map[string]int{}
+		names        = map[string]int{}
+		isDiffed     = make([]bool, len(actual))
+		isDefinition = map[string]bool{}
+	)
+	for i, item := range actual {
+		definitions[item.definition()] = i
+		names[item.Name] = i
+	}
+	for _, item := range declared {
+		if i, isActual := definitions[item.definition()]; isActual {
+			isDiffed[i] = true
+			isDefinition[item.definition()] = true
+		}
+	}
+	for _, item := range declared {
+		if isDefinition[item.definition()] {
+			continue
+		}
+		if i, isActual := names[item.Name]; isActual && item.Name != emptyString && !isDiffed[i] {
+			isDiffed[i] = true
+			report.Mismatched = append(report.Mismatched, SchemaMismatch{item, actual[i]})
+			continue
+		}
+		report.Missing = append(report.Missing, item)
+	}
+	for i, item := range actual {
+		if !isDiffed[i] {
+			report.Extra = append(report.Extra, item)
+		}
+	}
+	return report
+}
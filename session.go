@@ -22,18 +22,33 @@
 
 package gogm
 
-//Session provides access to the database
+import "context"
+
+//Session provides access to the database.
+//
+//Every operation has a Ctx variant that accepts a context.Context. The context is checked before
+//each statement and while records are streamed, and its deadline is passed to the database as the
+//transaction timeout so that Cypher still running when the deadline expires is terminated server side.
+//The variants without a context use context.Background()
+//
+//A session isn't safe for concurrent use, except for registering and disposing event listeners. Goroutines
+//should use their own session, Gogm.NewSession being safe for concurrent use
 type Session interface {
 	Load(object interface{}, ID interface{}, loadOptions *LoadOptions) error
 	LoadAll(objects interface{}, IDs interface{}, loadOptions *LoadOptions) error
 	Reload(loadOptions *LoadOptions, objects ...interface{}) error
 	Save(objects interface{}, saveOptions *SaveOptions) error
+	Flush(saveOptions *SaveOptions) error
+	IsDirty(object interface{}) (bool, error)
+	Changes(object interface{}) (*ChangeSet, error)
 	Delete(object interface{}, deleteOptions *DeleteOptions) error
+	HardDelete(object interface{}, deleteOptions *DeleteOptions) error
 	DeleteAll(object interface{}, deleteOptions *DeleteOptions) error
 	PurgeDatabase(deleteOptions *DeleteOptions) error
 	Clear() error
 	BeginTransaction(dbName string) (*transaction, error)
 	GetTransaction() *transaction
+	Transact(dbName string, work func(tx Session) error) error
 	QueryForObject(loadOptions *LoadOptions, object interface{}, cypher string, parameters map[string]interface{}) error
 	QueryForObjects(loadOptions *LoadOptions, objects interface{}, cypher string, parameters map[string]interface{}) error
 	Query(loadOptions *LoadOptions, cypher string, parameters map[string]interface{}, objects ...interface{}) ([]map[string]interface{}, error)
@@ -41,4 +56,21 @@ type Session interface {
 	Count(loadOptions *LoadOptions, cypher string, parameters map[string]interface{}) (int64, error)
 	RegisterEventListener(EventListener) error
 	DisposeEventListener(EventListener) error
+
+	LoadCtx(ctx context.Context, object interface{}, ID interface{}, loadOptions *LoadOptions) error
+	LoadAllCtx(ctx context.Context, objects interface{}, IDs interface{}, loadOptions *LoadOptions) error
+	ReloadCtx(ctx context.Context, loadOptions *LoadOptions, objects ...interface{}) error
+	SaveCtx(ctx context.Context, objects interface{}, saveOptions *SaveOptions) error
+	FlushCtx(ctx context.Context, saveOptions *SaveOptions) error
+	DeleteCtx(ctx context.Context, object interface{}, deleteOptions *DeleteOptions) error
+	HardDeleteCtx(ctx context.Context, object interface{}, deleteOptions *DeleteOptions) error
+	DeleteAllCtx(ctx context.Context, object interface{}, deleteOptions *DeleteOptions) error
+	PurgeDatabaseCtx(ctx context.Context, deleteOptions *DeleteOptions) error
+	BeginTransactionCtx(ctx context.Context, dbName string) (*transaction, error)
+	TransactCtx(ctx context.Context, dbName string, work func(tx Session) error) error
+	QueryForObjectCtx(ctx context.Context, loadOptions *LoadOptions, object interface{}, cypher string, parameters map[string]interface{}) error
+	QueryForObjectsCtx(ctx context.Context, loadOptions *LoadOptions, objects interface{}, cypher string, parameters map[string]interface{}) error
+	QueryCtx(ctx context.Context, loadOptions *LoadOptions, cypher string, parameters map[string]interface{}, objects ...interface{}) ([]map[string]interface{}, error)
+	CountEntitiesOfTypeCtx(ctx context.Context, loadOptions *LoadOptions, object interface{}) (int64, error)
+	CountCtx(ctx context.Context, loadOptions *LoadOptions, cypher string, parameters map[string]interface{}) (int64, error)
 }
@@ -23,6 +23,8 @@
 package gogm
 
 import (
+	"context"
+
 	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
 )
 
@@ -40,33 +42,122 @@ type sessionImpl struct {
 }
 
 func (s *sessionImpl) Load(object interface{}, ID interface{}, loadOptions *LoadOptions) error {
-	_, err := s.loader.load(object, ID, loadOptions, false)
+	return s.LoadCtx(context.Background(), object, ID, loadOptions)
+}
+
+func (s *sessionImpl) LoadCtx(ctx context.Context, object interface{}, ID interface{}, loadOptions *LoadOptions) error {
+	if loadOptions != nil {
+		ctx = withProfile(withDryRun(ctx, loadOptions.DryRun), loadOptions.Profile)
+	}
+	_, err := s.loader.load(ctx, object, ID, loadOptions, false)
 	return err
 }
 
 func (s *sessionImpl) LoadAll(objects interface{}, IDs interface{}, loadOptions *LoadOptions) error {
-	return s.loader.loadAll(objects, IDs, loadOptions)
+	return s.LoadAllCtx(context.Background(), objects, IDs, loadOptions)
+}
+
+func (s *sessionImpl) LoadAllCtx(ctx context.Context, objects interface{}, IDs interface{}, loadOptions *LoadOptions) error {
+	if loadOptions != nil {
+		ctx = withProfile(withDryRun(ctx, loadOptions.DryRun), loadOptions.Profile)
+	}
+	return s.loader.loadAll(ctx, objects, IDs, loadOptions)
 }
 
 func (s *sessionImpl) Reload(loadOptions *LoadOptions, objects ...interface{}) error {
-	return s.loader.reload(loadOptions, objects...)
+	return s.ReloadCtx(context.Background(), loadOptions, objects...)
+}
+
+func (s *sessionImpl) ReloadCtx(ctx context.Context, loadOptions *LoadOptions, objects ...interface{}) error {
+	if loadOptions != nil {
+		ctx = withProfile(withDryRun(ctx, loadOptions.DryRun), loadOptions.Profile)
+	}
+	return s.loader.reload(ctx, loadOptions, objects...)
 }
 
 func (s *sessionImpl) Save(objects interface{}, saveOptions *SaveOptions) error {
-	return s.saver.save(objects, saveOptions)
+	return s.SaveCtx(context.Background(), objects, saveOptions)
+}
+
+func (s *sessionImpl) SaveCtx(ctx context.Context, objects interface{}, saveOptions *SaveOptions) error {
+	if saveOptions != nil {
+		ctx = withProfile(withDryRun(withWriteStats(ctx, saveOptions.Stats), saveOptions.DryRun), saveOptions.Profile)
+	}
+	return s.saver.save(ctx, objects, saveOptions)
+}
+
+//Flush saves the changes made to every domain object loaded or saved in the session, in a single transaction.
+//Each object is saved up to the depth of saveOptions, so that new objects related to them are saved as well
+func (s *sessionImpl) Flush(saveOptions *SaveOptions) error {
+	return s.FlushCtx(context.Background(), saveOptions)
+}
+
+func (s *sessionImpl) FlushCtx(ctx context.Context, saveOptions *SaveOptions) error {
+	if saveOptions != nil {
+		ctx = withProfile(withDryRun(withWriteStats(ctx, saveOptions.Stats), saveOptions.DryRun), saveOptions.Profile)
+	}
+	return s.saver.flush(ctx, saveOptions)
+}
+
+//IsDirty returns true when object, a pointer to a pointer of domain object, has changes to save. An object not
+//managed by the session, i.e. neither loaded nor saved in it, is dirty
+func (s *sessionImpl) IsDirty(object interface{}) (bool, error) {
+	isManaged, changeSet, err := s.saver.changes(object)
+	if err != nil {
+		return false, err
+	}
+	return !isManaged || !changeSet.IsEmpty(), nil
+}
+
+//Changes returns the changes made to object, a pointer to a pointer of domain object, since it was loaded or saved
+func (s *sessionImpl) Changes(object interface{}) (*ChangeSet, error) {
+	_, changeSet, err := s.saver.changes(object)
+	return changeSet, err
 }
 
 func (s *sessionImpl) Delete(object interface{}, deleteOptions *DeleteOptions) error {
-	return s.deleter.delete(object, deleteOptions)
+	return s.DeleteCtx(context.Background(), object, deleteOptions)
+}
+
+func (s *sessionImpl) DeleteCtx(ctx context.Context, object interface{}, deleteOptions *DeleteOptions) error {
+	if deleteOptions != nil {
+		ctx = withDryRun(withWriteStats(ctx, deleteOptions.Stats), deleteOptions.DryRun)
+	}
+	return s.deleter.delete(ctx, object, deleteOptions, false)
+}
+
+func (s *sessionImpl) HardDelete(object interface{}, deleteOptions *DeleteOptions) error {
+	return s.HardDeleteCtx(context.Background(), object, deleteOptions)
+}
+
+func (s *sessionImpl) HardDeleteCtx(ctx context.Context, object interface{}, deleteOptions *DeleteOptions) error {
+	if deleteOptions != nil {
+		ctx = withDryRun(withWriteStats(ctx, deleteOptions.Stats), deleteOptions.DryRun)
+	}
+	return s.deleter.delete(ctx, object, deleteOptions, true)
 }
 
 func (s *sessionImpl) DeleteAll(objects interface{}, deleteOptions *DeleteOptions) error {
-	return s.deleter.deleteAll(objects, deleteOptions)
+	return s.DeleteAllCtx(context.Background(), objects, deleteOptions)
+}
+
+func (s *sessionImpl) DeleteAllCtx(ctx context.Context, objects interface{}, deleteOptions *DeleteOptions) error {
+	if deleteOptions != nil {
+		ctx = withDryRun(withWriteStats(ctx, deleteOptions.Stats), deleteOptions.DryRun)
+	}
+	return s.deleter.deleteAll(ctx, objects, deleteOptions)
 }
 
 func (s *sessionImpl) PurgeDatabase(deleteOptions *DeleteOptions) error {
+	return s.PurgeDatabaseCtx(context.Background(), deleteOptions)
+}
+
+func (s *sessionImpl) PurgeDatabaseCtx(ctx context.Context, deleteOptions *DeleteOptions) error {
 	var err error
-	if err = s.deleter.purgeDatabase(deleteOptions); err != nil {
+	if deleteOptions != nil {
+		ctx = withDryRun(withWriteStats(ctx, deleteOptions.Stats), deleteOptions.DryRun)
+	}
+	if err = s.deleter.purgeDatabase(ctx, deleteOptions); err != nil || isDryRun(ctx) {
 		return err
 	}
 	return s.store.clear()
@@ -77,13 +168,31 @@ func (s *sessionImpl) Clear() error {
 }
 
 func (s *sessionImpl) BeginTransaction(dbName string) (*transaction, error) {
-	return s.transactioner.beginTransaction(s, dbName)
+	return s.BeginTransactionCtx(context.Background(), dbName)
+}
+
+//BeginTransactionCtx begins a transaction bound to ctx. Statements run in the transaction fail once ctx is done
+//and the deadline of ctx, if any, becomes the transaction timeout
+func (s *sessionImpl) BeginTransactionCtx(ctx context.Context, dbName string) (*transaction, error) {
+	return s.transactioner.beginTransaction(ctx, s, dbName)
 }
 
 func (s *sessionImpl) GetTransaction() *transaction {
 	return s.transactioner.transaction
 }
 
+//Transact runs work in a transaction which is committed when work returns nil and rolled back when
+//work returns an error or panics. Transient failures, e.g. deadlocks or a leader switch, are retried
+//with backoff, hence work may run more than once. The session store is restored to its state before
+//the transaction on every retry and on rollback
+func (s *sessionImpl) Transact(dbName string, work func(tx Session) error) error {
+	return s.TransactCtx(context.Background(), dbName, work)
+}
+
+func (s *sessionImpl) TransactCtx(ctx context.Context, dbName string, work func(tx Session) error) error {
+	return s.transactioner.transact(ctx, s, dbName, work)
+}
+
 //Precondition:
 // * object is a pointer to a pointer of domain object: **<domainObject>
 // * cypher returns one record with a column of domain object(s)
@@ -93,7 +202,14 @@ func (s *sessionImpl) GetTransaction() *transaction {
 //Post condition:
 //Polulated domain objects
 func (s *sessionImpl) QueryForObject(loadOptions *LoadOptions, object interface{}, cypher string, parameters map[string]interface{}) error {
-	return s.queryer.queryForObject(loadOptions, object, cypher, parameters)
+	return s.QueryForObjectCtx(context.Background(), loadOptions, object, cypher, parameters)
+}
+
+func (s *sessionImpl) QueryForObjectCtx(ctx context.Context, loadOptions *LoadOptions, object interface{}, cypher string, parameters map[string]interface{}) error {
+	if loadOptions != nil {
+		ctx = withProfile(withDryRun(ctx, loadOptions.DryRun), loadOptions.Profile)
+	}
+	return s.queryer.queryForObject(ctx, loadOptions, object, cypher, parameters)
 }
 
 //Precondition:
@@ -105,19 +221,47 @@ func (s *sessionImpl) QueryForObject(loadOptions *LoadOptions, object interface{
 //Post condition:
 //Polulated domain objects
 func (s *sessionImpl) QueryForObjects(loadOptions *LoadOptions, objects interface{}, cypher string, parameters map[string]interface{}) error {
-	return s.queryer.queryForObjects(loadOptions, objects, cypher, parameters)
+	return s.QueryForObjectsCtx(context.Background(), loadOptions, objects, cypher, parameters)
+}
+
+func (s *sessionImpl) QueryForObjectsCtx(ctx context.Context, loadOptions *LoadOptions, objects interface{}, cypher string, parameters map[string]interface{}) error {
+	if loadOptions != nil {
+		ctx = withProfile(withDryRun(ctx, loadOptions.DryRun), loadOptions.Profile)
+	}
+	return s.queryer.queryForObjects(ctx, loadOptions, objects, cypher, parameters)
 }
 
 func (s *sessionImpl) Query(loadOptions *LoadOptions, cypher string, parameters map[string]interface{}, objects ...interface{}) ([]map[string]interface{}, error) {
-	return s.queryer.query(loadOptions, cypher, parameters, objects...)
+	return s.QueryCtx(context.Background(), loadOptions, cypher, parameters, objects...)
+}
+
+func (s *sessionImpl) QueryCtx(ctx context.Context, loadOptions *LoadOptions, cypher string, parameters map[string]interface{}, objects ...interface{}) ([]map[string]interface{}, error) {
+	if loadOptions != nil {
+		ctx = withProfile(withDryRun(ctx, loadOptions.DryRun), loadOptions.Profile)
+	}
+	return s.queryer.query(ctx, loadOptions, cypher, parameters, objects...)
 }
 
 func (s *sessionImpl) CountEntitiesOfType(loadOptions *LoadOptions, object interface{}) (int64, error) {
-	return s.queryer.countEntitiesOfType(loadOptions, object)
+	return s.CountEntitiesOfTypeCtx(context.Background(), loadOptions, object)
+}
+
+func (s *sessionImpl) CountEntitiesOfTypeCtx(ctx context.Context, loadOptions *LoadOptions, object interface{}) (int64, error) {
+	if loadOptions != nil {
+		ctx = withProfile(withDryRun(ctx, loadOptions.DryRun), loadOptions.Profile)
+	}
+	return s.queryer.countEntitiesOfType(ctx, loadOptions, object)
 }
 
 func (s *sessionImpl) Count(loadOptions *LoadOptions, cypher string, parameters map[string]interface{}) (int64, error) {
-	return s.queryer.count(loadOptions, cypher, parameters)
+	return s.CountCtx(context.Background(), loadOptions, cypher, parameters)
+}
+
+func (s *sessionImpl) CountCtx(ctx context.Context, loadOptions *LoadOptions, cypher string, parameters map[string]interface{}) (int64, error) {
+	if loadOptions != nil {
+		ctx = withProfile(withDryRun(ctx, loadOptions.DryRun), loadOptions.Profile)
+	}
+	return s.queryer.count(ctx, loadOptions, cypher, parameters)
 }
 
 func (s *sessionImpl) RegisterEventListener(eventListener EventListener) error {
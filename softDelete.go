@@ -0,0 +1,93 @@
+// MIT License
+//
+// Copyright (c) 2022 pmadhav
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package gogm
+
+import (
+	"errors"
+	"reflect"
+	"time"
+)
+
+func getSoftDeleteBackendName(structFields map[string]*reflect.StructField) (string, error) {
+	var softDeleteBackendName string
+	for backendName, structField := range structFields {
+		if len(getNamespacedTag(structField.Tag).get(softDeleteTag)) > 0 {
+			if softDeleteBackendName != emptyString {
+				return emptyString, errors.New("Expected at most 1 field to be tagged 'softDelete'")
+			}
+			switch structField.Type.Kind() {
+			case reflect.Bool, reflect.Int64, reflect.Uint64:
+				softDeleteBackendName = backendName
+			default:
+				if structField.Type != typeOfTime {
+					return emptyString, errors.New("invalid softDelete type. Soft delete type must be bool, time.Time or an epoch in seconds of type int64 or uint64")
+				}
+				softDeleteBackendName = backendName
+			}
+		}
+	}
+	return softDeleteBackendName, nil
+}
+
+//getSoftDeletedValue returns the value marking an entity as soft deleted at now
+func getSoftDeletedValue(t reflect.Type, now time.Time) reflect.Value {
+	softDeleted := reflect.New(t).Elem()
+	switch {
+	case t == typeOfTime:
+		softDeleted.Set(reflect.ValueOf(now))
+	case t.Kind() == reflect.Bool:
+		softDeleted.SetBool(true)
+	case t.Kind() == reflect.Int64:
+		softDeleted.SetInt(now.Unix())
+	case t.Kind() == reflect.Uint64:
+		softDeleted.SetUint(uint64(now.Unix()))
+	}
+	return softDeleted
+}
+
+//isSoftDeleted returns whether the domain object of g is marked as soft deleted
+func isSoftDeleted(g graph, registry *registry, dbName string) (bool, error) {
+	if g.getValue() == nil || !g.getValue().IsValid() {
+		return false, nil
+	}
+	metadata, err := registry.get(g.getValue().Type(), dbName)
+	if err != nil {
+		return false, err
+	}
+	softDeleteName := metadata.getSoftDeleteBackendName()
+	if softDeleteName == emptyString {
+		return false, nil
+	}
+	return !g.getValue().Elem().FieldByName(metadata.getPropertyStructFields()[softDeleteName].Name).IsZero(), nil
+}
+
+//getSoftDeleteFilter returns the condition excluding soft deleted entities bound to variable, and its parameters.
+//An entity is soft deleted when its soft delete property isn't the zero value of the soft delete field
+func getSoftDeleteFilter(variable string, metadata metadata) (string, map[string]interface{}) {
+	softDeleteName := metadata.getSoftDeleteBackendName()
+	if softDeleteName == emptyString {
+		return emptyString, nil
+	}
+	notSoftDeleted := reflect.Zero(metadata.getPropertyStructFields()[softDeleteName].Type).Interface()
+	return `coalesce(` + variable + `.` + softDeleteName + `, $notSoftDeleted) = $notSoftDeleted`, map[string]interface{}{"notSoftDeleted": notSoftDeleted}
+}
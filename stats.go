@@ -0,0 +1,150 @@
+// MIT License
+//
+// Copyright (c) 2022 pmadhav
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package gogm
+
+import (
+	"context"
+
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+)
+
+//WriteStats are the counters of the statements run by a write operation, summed from the summaries of the
+//driver, and the notifications of the server about them, e.g. deprecation warnings. Statements run in a
+//transaction are counted when they run, even if the transaction is rolled back later
+type WriteStats struct {
+	Statements           int
+	NodesCreated         int
+	NodesDeleted         int
+	RelationshipsCreated int
+	RelationshipsDeleted int
+	PropertiesSet        int
+	LabelsAdded          int
+	LabelsRemoved        int
+	IndexesAdded         int
+	IndexesRemoved       int
+	ConstraintsAdded     int
+	ConstraintsRemoved   int
+	Notifications        []Notification
+}
+
+//Notification is a notification of the server about a statement
+type Notification struct {
+	Code        string
+	Title       string
+	Description string
+	Severity    string
+	Cypher      string
+
+	//Line, Column and Offset are the position in Cypher the notification is about, if any
+	Line   int
+	Column int
+	Offset int
+}
+
+type writeStatsKey struct{}
+
+//withWriteStats returns a context collecting the stats of the statements run with it into stats, reset first
+func withWriteStats(ctx context.Context, stats *WriteStats) context.Context {
+	if stats == nil {
+		return ctx
+	}
+	*stats = WriteStats{}
+	return context.WithValue(ctx, writeStatsKey{}, stats)
+}
+
+//getWriteStats returns the stats collected by ctx, nil when it doesn't collect any
+func getWriteStats(ctx context.Context) *WriteStats {
+	stats, _ := ctx.Value(writeStatsKey{}).(*WriteStats)
+	return stats
+}
+
+//newAttemptStats returns the stats of an attempt of a transaction function, nil when ctx doesn't collect any.
+//They're merged into the stats of ctx once the transaction function succeeds, so that retries aren't counted
+func newAttemptStats(ctx context.Context) *WriteStats {
+	if getWriteStats(ctx) == nil {
+		return nil
+	}
+	return &WriteStats{}
+}
+
+//consume adds the summary of result, whose records are collected, to the stats
+func (w *WriteStats) consume(result neo4j.Result) error {
+	if w == nil {
+		return nil
+	}
+	summary, err := result.Consume()
+	if err != nil {
+		return err
+	}
+	w.add(summary)
+	return nil
+}
+
+func (w *WriteStats) add(summary neo4j.ResultSummary) {
+	counters := summary.Counters()
+	w.Statements++
+	w.NodesCreated += counters.NodesCreated()
+	w.NodesDeleted += counters.NodesDeleted()
+	w.RelationshipsCreated += counters.RelationshipsCreated()
+	w.RelationshipsDeleted += counters.RelationshipsDeleted()
+	w.PropertiesSet += counters.PropertiesSet()
+	w.LabelsAdded += counters.LabelsAdded()
+	w.LabelsRemoved += counters.LabelsRemoved()
+	w.IndexesAdded += counters.IndexesAdded()
+	w.IndexesRemoved += counters.IndexesRemoved()
+	w.ConstraintsAdded += counters.ConstraintsAdded()
+	w.ConstraintsRemoved += counters.ConstraintsRemoved()
+	for _, n := range summary.Notifications() {
+		notification := Notification{
+			Code:        n.Code(),
+			Title:       n.Title(),
+			Description: n.Description(),
+			Severity:    n.Severity(),
+			Cypher:      summary.Query().Text()}
+		if position := n.Position(); position != nil {
+			notification.Line = position.Line()
+			notification.Column = position.Column()
+			notification.Offset = position.Offset()
+		}
+		w.Notifications = append(w.Notifications, notification)
+	}
+}
+
+func (w *WriteStats) merge(other *WriteStats) {
+	if w == nil || other == nil {
+		return
+	}
+	w.Statements += other.Statements
+	w.NodesCreated += other.NodesCreated
+	w.NodesDeleted += other.NodesDeleted
+	w.RelationshipsCreated += other.RelationshipsCreated
+	w.RelationshipsDeleted += other.RelationshipsDeleted
+	w.PropertiesSet += other.PropertiesSet
+	w.LabelsAdded += other.LabelsAdded
+	w.LabelsRemoved += other.LabelsRemoved
+	w.IndexesAdded += other.IndexesAdded
+	w.IndexesRemoved += other.IndexesRemoved
+	w.ConstraintsAdded += other.ConstraintsAdded
+	w.ConstraintsRemoved += other.ConstraintsRemoved
+	w.Notifications = append(w.Notifications, other.Notifications...)
+}
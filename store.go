@@ -28,7 +28,8 @@ import (
 )
 
 //store is a container of graphs. An instance of it is used as a cache for the session and other instances
-// of it are used for keeping track of graphs during a graph traversal.
+// of it are used for keeping track of graphs during a graph traversal. A store is safe for concurrent use,
+// the graphs it contains aren't.
 type store interface {
 	// all returns all graphs in the store
 	all() []graph
@@ -58,6 +59,12 @@ type store interface {
 	// getByCustomID returns the graph with whose custom ID is interface{}
 	getByCustomID(reflect.Value, reflect.Type, interface{}) graph
 
+	// snapshot captures the state of the store and of the graphs in it
+	snapshot() *storeSnapshot
+
+	// restore brings the store, its graphs and the IDs of their domain objects back to the state of a snapshot
+	restore(*storeSnapshot)
+
 	// // print prints the store
 	// print()
 }
@@ -69,19 +76,20 @@ type storeImpl struct {
 	relationships  map[int64]graph
 	relationshipsA map[int64]map[int64]*int64
 	customIDs      map[string]map[interface{}]*int64
-	nodesMu        sync.Mutex
+	mu             sync.RWMutex
 }
 
 func newstore(registry *registry) *storeImpl {
-	return &storeImpl{registry, map[int64]graph{}, map[int64]graph{}, map[int64]map[int64]*int64{}, map[string]map[interface{}]*int64{}, sync.Mutex{}}
+	return &storeImpl{registry, map[int64]graph{}, map[int64]graph{}, map[int64]map[int64]*int64{}, map[string]map[interface{}]*int64{}, sync.RWMutex{}}
 }
 
 func (s *storeImpl) get(g graph) graph {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
 	var storedGraph graph
 	switch t := reflect.TypeOf(g); t {
 	case typeOfPrivateNode:
-		s.nodesMu.Lock()
-		defer s.nodesMu.Unlock()
 		storedGraph = s.nodes[g.getID()]
 	case typeOfPrivateRelationship:
 		internalID := g.getID()
@@ -102,10 +110,11 @@ func (s *storeImpl) get(g graph) graph {
 }
 
 func (s *storeImpl) save(g graph, dbName string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	switch t := reflect.TypeOf(g); t {
 	case typeOfPrivateNode:
-		s.nodesMu.Lock()
-		defer s.nodesMu.Unlock()
 		s.nodes[g.getID()] = g
 	case typeOfPrivateRelationship:
 		s.relationships[g.getID()] = g
@@ -134,6 +143,12 @@ func (s *storeImpl) save(g graph, dbName string) {
 }
 
 func (s *storeImpl) delete(g graph, dbName string) ([]graph, []graph) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.deleteGraph(g, dbName)
+}
+
+func (s *storeImpl) deleteGraph(g graph, dbName string) ([]graph, []graph) {
 	var deletedGraphs []graph
 	var updatedGraphs []graph
 	switch t := reflect.TypeOf(g); t {
@@ -141,7 +156,7 @@ func (s *storeImpl) delete(g graph, dbName string) ([]graph, []graph) {
 		node := s.nodes[g.getID()]
 		if node != nil {
 			for _, relatedGraph := range node.getRelatedGraphs() {
-				relatedDeletedGraphs, relatedUpdatedGraphs := s.delete(relatedGraph, dbName)
+				relatedDeletedGraphs, relatedUpdatedGraphs := s.deleteGraph(relatedGraph, dbName)
 				if relatedUpdatedGraphs != nil {
 					if len(relatedUpdatedGraphs) == 2 {
 						if relatedUpdatedGraphs[0] == node {
@@ -190,6 +205,9 @@ func (s *storeImpl) delete(g graph, dbName string) ([]graph, []graph) {
 }
 
 func (s *storeImpl) clear() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	s.nodes = map[int64]graph{}
 	s.relationships = map[int64]graph{}
 	s.relationshipsA = map[int64]map[int64]*int64{}
@@ -198,23 +216,33 @@ func (s *storeImpl) clear() error {
 }
 
 func (s *storeImpl) purge(dbName string) []graph {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	var deletedGraphs []graph
 	for _, node := range s.nodes {
-		nodeDeletedGraphs, _ := s.delete(node, dbName)
+		nodeDeletedGraphs, _ := s.deleteGraph(node, dbName)
 		deletedGraphs = append(deletedGraphs, nodeDeletedGraphs...)
 	}
 	return deletedGraphs
 }
 
 func (s *storeImpl) node(ID int64) graph {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 	return s.nodes[ID]
 }
 
 func (s *storeImpl) relationship(ID int64) graph {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 	return s.relationships[ID]
 }
 
 func (s *storeImpl) all() []graph {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
 	var allGraphs []graph
 	for _, node := range s.nodes {
 		allGraphs = append(allGraphs, node)
@@ -226,6 +254,9 @@ func (s *storeImpl) all() []graph {
 }
 
 func (s *storeImpl) getByCustomID(v reflect.Value, typeOfRefGraph reflect.Type, idValue interface{}) graph {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
 	typeName := v.Type().String()
 	mapToSearch := s.nodes
 	if typeOfRefGraph == typeOfPrivateRelationship {
@@ -238,6 +269,193 @@ func (s *storeImpl) getByCustomID(v reflect.Value, typeOfRefGraph reflect.Type,
 	return nil
 }
 
+//storeSnapshot is the state of a store and of the graphs in it at a point in time
+type storeSnapshot struct {
+	nodes          map[int64]graph
+	relationships  map[int64]graph
+	relationshipsA map[int64]map[int64]*int64
+	customIDs      map[string]map[interface{}]*int64
+	graphs         map[graph]*graphSnapshot
+	domainFields   domainFields
+}
+
+//domainFields keeps the fields of domain objects set by the OGM on save, by pointer, so that they can be restored
+type domainFields map[interface{}][]domainField
+
+//domainField is a field of a domain object set by the OGM on save, i.e. its version or a timestamp, with the value it
+//had when it was kept
+type domainField struct {
+	field reflect.Value
+	value reflect.Value
+}
+
+//graphSnapshot is the state of a graph at a point in time. Stored graphs are updated in place, e.g. when
+//a related graph is deleted, hence their state is captured along with the store
+type graphSnapshot struct {
+	ID            int64
+	label         string
+	depth         *int
+	properties    map[string]interface{}
+	relatedGraphs map[int64]graph
+}
+
+func (s *storeImpl) snapshot() *storeSnapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	snapshot := &storeSnapshot{
+		nodes:          make(map[int64]graph, len(s.nodes)),
+		relationships:  make(map[int64]graph, len(s.relationships)),
+		relationshipsA: make(map[int64]map[int64]*int64, len(s.relationshipsA)),
+		customIDs:      make(map[string]map[interface{}]*int64, len(s.customIDs)),
+		graphs:         map[graph]*graphSnapshot{},
+		domainFields:   domainFields{}}
+
+	for ID, g := range s.nodes {
+		snapshot.nodes[ID] = g
+		snapshot.graphs[g] = getGraphSnapshot(g)
+	}
+	for ID, g := range s.relationships {
+		snapshot.relationships[ID] = g
+		snapshot.graphs[g] = getGraphSnapshot(g)
+	}
+	for startID, endIDs := range s.relationshipsA {
+		snapshot.relationshipsA[startID] = make(map[int64]*int64, len(endIDs))
+		for endID, ID := range endIDs {
+			snapshot.relationshipsA[startID][endID] = ID
+		}
+	}
+	for typeName, IDs := range s.customIDs {
+		snapshot.customIDs[typeName] = make(map[interface{}]*int64, len(IDs))
+		for customID, ID := range IDs {
+			snapshot.customIDs[typeName][customID] = ID
+		}
+	}
+	return snapshot
+}
+
+func (s *storeImpl) restore(snapshot *storeSnapshot) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	//Domain objects stored after the snapshot was taken were never persisted
+	for _, graphs := range [2]map[int64]graph{s.nodes, s.relationships} {
+		for _, g := range graphs {
+			if snapshot.graphs[g] == nil && g.getValue() != nil && g.getValue().IsValid() {
+				*getIDAddr(g) = nil
+			}
+		}
+	}
+
+	for g, graphSnapshot := range snapshot.graphs {
+		g.setID(graphSnapshot.ID)
+		g.setLabel(graphSnapshot.label)
+		g.setDepth(graphSnapshot.depth)
+		g.setProperties(copyProperties(graphSnapshot.properties))
+		relatedGraphs := g.getRelatedGraphs()
+		for ID := range relatedGraphs {
+			delete(relatedGraphs, ID)
+		}
+		for ID, relatedGraph := range graphSnapshot.relatedGraphs {
+			relatedGraphs[ID] = relatedGraph
+		}
+		if g.getValue() != nil && g.getValue().IsValid() {
+			ID := graphSnapshot.ID
+			*getIDAddr(g) = &ID
+		}
+	}
+
+	snapshot.domainFields.restore()
+
+	restored := snapshot.snapshotCopy()
+	s.nodes, s.relationships, s.relationshipsA, s.customIDs = restored.nodes, restored.relationships, restored.relationshipsA, restored.customIDs
+}
+
+//keep keeps the version and the timestamps of the domain object of g, unless they're already kept
+func (d domainFields) keep(g graph, registry *registry, dbName string) {
+	if g.getValue() == nil || !g.getValue().IsValid() || g.getValue().IsNil() {
+		return
+	}
+	pointer := g.getValue().Interface()
+	if _, isKept := d[pointer]; isKept {
+		return
+	}
+	metadata, err := registry.get(g.getValue().Type(), dbName)
+	if err != nil {
+		return
+	}
+	fields := []domainField{}
+	for _, get := range []func(reflect.Value) (string, reflect.Value){metadata.getVersion, metadata.getCreatedAt, metadata.getUpdatedAt} {
+		if name, field := get(*g.getValue()); name != emptyString {
+			value := reflect.New(field.Type()).Elem()
+			value.Set(field)
+			fields = append(fields, domainField{field, value})
+		}
+	}
+	d[pointer] = fields
+}
+
+//restore sets the kept fields back to the values they had when they were kept
+func (d domainFields) restore() {
+	for _, fields := range d {
+		for _, field := range fields {
+			field.field.Set(field.value)
+		}
+	}
+}
+
+//snapshotCopy copies the maps of the snapshot so that it can be restored more than once
+func (snapshot *storeSnapshot) snapshotCopy() *storeSnapshot {
+	restored := &storeSnapshot{
+		nodes:          make(map[int64]graph, len(snapshot.nodes)),
+		relationships:  make(map[int64]graph, len(snapshot.relationships)),
+		relationshipsA: make(map[int64]map[int64]*int64, len(snapshot.relationshipsA)),
+		customIDs:      make(map[string]map[interface{}]*int64, len(snapshot.customIDs))}
+	for ID, g := range snapshot.nodes {
+		restored.nodes[ID] = g
+	}
+	for ID, g := range snapshot.relationships {
+		restored.relationships[ID] = g
+	}
+	for startID, endIDs := range snapshot.relationshipsA {
+		restored.relationshipsA[startID] = make(map[int64]*int64, len(endIDs))
+		for endID, ID := range endIDs {
+			restored.relationshipsA[startID][endID] = ID
+		}
+	}
+	for typeName, IDs := range snapshot.customIDs {
+		restored.customIDs[typeName] = make(map[interface{}]*int64, len(IDs))
+		for customID, ID := range IDs {
+			restored.customIDs[typeName][customID] = ID
+		}
+	}
+	return restored
+}
+
+func getGraphSnapshot(g graph) *graphSnapshot {
+	relatedGraphs := make(map[int64]graph, len(g.getRelatedGraphs()))
+	for ID, relatedGraph := range g.getRelatedGraphs() {
+		relatedGraphs[ID] = relatedGraph
+	}
+	return &graphSnapshot{
+		ID:            g.getID(),
+		label:         g.getLabel(),
+		depth:         g.getDepth(),
+		properties:    copyProperties(g.getProperties()),
+		relatedGraphs: relatedGraphs}
+}
+
+func copyProperties(properties map[string]interface{}) map[string]interface{} {
+	if properties == nil {
+		return nil
+	}
+	copied := make(map[string]interface{}, len(properties))
+	for name, property := range properties {
+		copied[name] = property
+	}
+	return copied
+}
+
 func unwind(g graph, depth int, dbName string) store {
 	visited := newstore(nil)
 	maxDepth := depth * 2
@@ -37,6 +37,15 @@ var (
 	propertyNameTag = "name"
 	uniqueTag       = "unique"
 	indexTag        = "index"
+	nodeKeyTag      = "nodeKey"
+	existsTag       = "exists"
+	textTag         = "text"
+	fullTextTag     = "fulltext"
+	versionTag      = "version"
+	createdAtTag    = "createdAt"
+	updatedAtTag    = "updatedAt"
+	softDeleteTag   = "softDelete"
+	cascadeTag      = "cascade"
 )
 
 var (
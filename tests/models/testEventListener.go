@@ -92,3 +92,33 @@ func (e *TestEventListener) OnPostDelete(event gogm.Event) {
 		}
 	}
 }
+
+//RecordingEventListener records the names of the Node16 objects it's notified of after save, and before and after
+//delete
+type RecordingEventListener struct {
+	Saved      []string
+	PreDeleted []string
+	Deleted    []string
+}
+
+func (e *RecordingEventListener) OnPreSave(event gogm.Event) {}
+
+func (e *RecordingEventListener) OnPostSave(event gogm.Event) {
+	if object, ok := event.GetObject().(*Node16); ok {
+		e.Saved = append(e.Saved, object.Name)
+	}
+}
+
+func (e *RecordingEventListener) OnPostLoad(event gogm.Event) {}
+
+func (e *RecordingEventListener) OnPreDelete(event gogm.Event) {
+	if object, ok := event.GetObject().(*Node16); ok {
+		e.PreDeleted = append(e.PreDeleted, object.Name)
+	}
+}
+
+func (e *RecordingEventListener) OnPostDelete(event gogm.Event) {
+	if object, ok := event.GetObject().(*Node16); ok && event.GetLifeCycle() == gogm.DELETE {
+		e.Deleted = append(e.Deleted, object.Name)
+	}
+}
@@ -131,6 +131,61 @@ type Node10 struct {
 	// Duration1 *neo4j.Duration
 }
 
+type Node11 struct {
+	TestNodeEntity
+	Name    string
+	Version int64 `gogm:"version"`
+}
+
+type Node12 struct {
+	TestNodeEntity
+	Name     string
+	Created  time.Time `gogm:"createdAt"`
+	Modified int64     `gogm:"updatedAt"`
+}
+
+type Node13 struct {
+	TestNodeEntity
+	Name    string
+	Removed bool `gogm:"softDelete"`
+}
+
+type Node14 struct {
+	TestNodeEntity
+	Name    string
+	Parts   []*Node15 `gogm:"reltype:PART,cascade:delete"`
+	Related []*Node16
+}
+
+type Node15 struct {
+	TestNodeEntity
+	Name    string
+	Details []*Node16 `gogm:"reltype:DETAIL,cascade:delete"`
+}
+
+type Node16 struct {
+	TestNodeEntity
+	Name string
+}
+
+type Node17 struct {
+	TestNodeEntity
+	Code  string `gogm:"unique"`
+	Title string `gogm:"index"`
+	Year  int64  `gogm:"index"`
+}
+
+type Node18 struct {
+	TestNodeEntity
+	Title   string `gogm:"index:byTitle,index:byTitleYear,fulltext:search"`
+	Year    int64  `gogm:"index:byTitleYear"`
+	Summary string `gogm:"text,fulltext:search"`
+	Isbn    string `gogm:"nodeKey:edition"`
+	Edition int64  `gogm:"nodeKey:edition"`
+	Author  string `gogm:"exists"`
+	Cites   []*Citation
+}
+
 type InvalidID struct {
 	TestNodeEntity
 	TestId *string `gogm:"id,name:IDs"`
@@ -0,0 +1,76 @@
+// MIT License
+//
+// Copyright (c) 2022 pmadhav
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package models
+
+import (
+	"context"
+	"sync"
+
+	gogm "github.com/pmadhav/neo4j-go-ogm"
+)
+
+type contextKey string
+
+//RecordingQueryHook records the queries reported to it
+type RecordingQueryHook struct {
+	Queries []gogm.Query
+
+	//Before counts the calls of BeforeQuery, whose context is expected by AfterQuery
+	Before int
+	mu     sync.Mutex
+}
+
+func (h *RecordingQueryHook) BeforeQuery(ctx context.Context, query *gogm.Query) context.Context {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.Before++
+	return context.WithValue(ctx, contextKey("query"), query.Cypher)
+}
+
+func (h *RecordingQueryHook) AfterQuery(ctx context.Context, query *gogm.Query) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if ctx.Value(contextKey("query")) == query.Cypher {
+		h.Queries = append(h.Queries, *query)
+	}
+}
+
+//RecordingLogger records the messages logged by level
+type RecordingLogger struct {
+	Messages map[string][]string
+	mu       sync.Mutex
+}
+
+func (l *RecordingLogger) log(level string, msg string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.Messages == nil {
+		l.Messages = map[string][]string{}
+	}
+	l.Messages[level] = append(l.Messages[level], msg)
+}
+
+func (l *RecordingLogger) Debug(msg string, args ...interface{}) { l.log("debug", msg) }
+func (l *RecordingLogger) Info(msg string, args ...interface{})  { l.log("info", msg) }
+func (l *RecordingLogger) Warn(msg string, args ...interface{})  { l.log("warn", msg) }
+func (l *RecordingLogger) Error(msg string, args ...interface{}) { l.log("error", msg) }
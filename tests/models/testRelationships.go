@@ -59,3 +59,10 @@ type SimpleRelationshipSameNode struct {
 	N52  *Node5 `gogm:"endNode"`
 	Name string
 }
+
+type Citation struct {
+	TestRelationshipEntity `gogm:"reltype:CITES"`
+	From                   *Node18 `gogm:"startNode"`
+	To                     *Node18 `gogm:"endNode"`
+	Page                   int64   `gogm:"index"`
+}
@@ -0,0 +1,85 @@
+// MIT License
+//
+// Copyright (c) 2022 pmadhav
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package gogm
+
+import (
+	"errors"
+	"reflect"
+	"time"
+)
+
+var typeOfTime = reflect.TypeOf(time.Time{})
+
+func getTimestampBackendName(structFields map[string]*reflect.StructField, timestampTag string) (string, error) {
+	var timestampBackendName string
+	for backendName, structField := range structFields {
+		if len(getNamespacedTag(structField.Tag).get(timestampTag)) > 0 {
+			if timestampBackendName != emptyString {
+				return emptyString, errors.New("Expected at most 1 field to be tagged '" + timestampTag + "'")
+			}
+			switch structField.Type.Kind() {
+			case reflect.Int64, reflect.Uint64:
+				timestampBackendName = backendName
+			default:
+				if structField.Type != typeOfTime {
+					return emptyString, errors.New("invalid " + timestampTag + " type. Timestamp type must be time.Time or an epoch in seconds of type int64 or uint64")
+				}
+				timestampBackendName = backendName
+			}
+		}
+	}
+	return timestampBackendName, nil
+}
+
+//setTimestamps sets the createdAt timestamp of a new g and the updatedAt timestamp of g to now. It returns
+//whether g has any of these timestamps, in which case its properties have changed
+func setTimestamps(g graph, registry *registry, dbName string, now time.Time) (bool, error) {
+	if g.getValue() == nil || !g.getValue().IsValid() {
+		return false, nil
+	}
+	metadata, err := registry.get(g.getValue().Type(), dbName)
+	if err != nil {
+		return false, err
+	}
+	isTimestamped := false
+	setTimestamp := func(name string, timestamp reflect.Value) {
+		if name == emptyString {
+			return
+		}
+		switch {
+		case timestamp.Type() == typeOfTime:
+			timestamp.Set(reflect.ValueOf(now))
+		case timestamp.Kind() == reflect.Int64:
+			timestamp.SetInt(now.Unix())
+		case timestamp.Kind() == reflect.Uint64:
+			timestamp.SetUint(uint64(now.Unix()))
+		}
+		g.getProperties()[name] = timestamp.Interface()
+		isTimestamped = true
+	}
+	if g.getID() < 0 {
+		setTimestamp(metadata.getCreatedAt(*g.getValue()))
+	}
+	setTimestamp(metadata.getUpdatedAt(*g.getValue()))
+	return isTimestamped, nil
+}
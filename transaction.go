@@ -23,6 +23,8 @@
 package gogm
 
 import (
+	"context"
+
 	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
 )
 
@@ -31,15 +33,32 @@ type transaction struct {
 	session          neo4j.Session
 	close            transactionEnder
 	dbName           string
+	ctx              context.Context
+	store            store
+	snapshot         *storeSnapshot
+	eventer          *eventer
+	managed          bool
 }
 
-func newTransaction(driver neo4j.Driver, transactionEnder transactionEnder, accessMode neo4j.AccessMode, dbName string) (*transaction, error) {
+//newTransaction begins a transaction. store is snapshotted so that rolling back the transaction
+//also rolls back the session store and the IDs, versions and timestamps of the domain objects saved or deleted in it.
+//Post save and post delete events of eventer are deferred until the transaction commits
+func newTransaction(ctx context.Context, driver neo4j.Driver, transactionEnder transactionEnder, accessMode neo4j.AccessMode, dbName string, store store, eventer *eventer) (*transaction, error) {
 
 	var (
-		err     error
-		session neo4j.Session
+		err         error
+		session     neo4j.Session
+		configurers []func(*neo4j.TransactionConfig)
 	)
 
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if configurers, err = transactionConfigurers(ctx); err != nil {
+		return nil, err
+	}
+
 	sessionConfig := neo4j.SessionConfig{
 		AccessMode: accessMode,
 	}
@@ -51,30 +70,75 @@ func newTransaction(driver neo4j.Driver, transactionEnder transactionEnder, acce
 	session = driver.NewSession(sessionConfig)
 
 	var neo4jtransaction neo4j.Transaction
-	if neo4jtransaction, err = session.BeginTransaction(); err != nil {
+	if neo4jtransaction, err = session.BeginTransaction(configurers...); err != nil {
 		session.Close()
 		return nil, err
 	}
 
+	eventer.deferEvents()
 	return &transaction{
 		neo4jTransaction: neo4jtransaction,
 		session:          session,
 		close:            transactionEnder,
-		dbName:           dbName}, nil
+		dbName:           dbName,
+		ctx:              ctx,
+		store:            store,
+		snapshot:         store.snapshot(),
+		eventer:          eventer}, nil
 }
 
-func (t *transaction) run(cql string, params map[string]interface{}) (neo4j.Result, error) {
+//run runs cql within the transaction. Both the context the transaction was started with and
+//the context of the calling operation must not be done
+func (t *transaction) run(ctx context.Context, cql string, params map[string]interface{}) (neo4j.Result, error) {
+	if err := t.ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	return t.neo4jTransaction.Run(cql, params)
 }
 
 func (t *transaction) Commit() error {
-	return t.neo4jTransaction.Commit()
+	if t.managed {
+		return errManagedTransaction()
+	}
+	if err := t.neo4jTransaction.Commit(); err != nil {
+		return mapDriverError(err)
+	}
+	t.snapshot = nil
+	t.eventer.flushEvents()
+	return nil
 }
 
+//RollBack rolls back the transaction along with the changes made to the session store since the transaction began.
+//Domain objects saved in the transaction get back the ID, the version and the timestamps they had when the transaction
+//began. Their other fields are left as is, use Reload to sync them with the database. The post save and post delete events fired in the
+//transaction are discarded
 func (t *transaction) RollBack() error {
-	return t.neo4jTransaction.Rollback()
+	if t.managed {
+		return errManagedTransaction()
+	}
+	if err := t.neo4jTransaction.Rollback(); err != nil {
+		return err
+	}
+	t.restore()
+	return nil
 }
 
+//Close closes the transaction. A transaction neither committed nor rolled back is rolled back
 func (t *transaction) Close() error {
-	return t.close()
+	if err := t.close(); err != nil {
+		return err
+	}
+	t.restore()
+	return nil
+}
+
+func (t *transaction) restore() {
+	if t.snapshot != nil {
+		t.store.restore(t.snapshot)
+		t.snapshot = nil
+		t.eventer.discardEvents()
+	}
 }
@@ -23,6 +23,7 @@
 package gogm
 
 import (
+	"context"
 	"errors"
 
 	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
@@ -39,13 +40,19 @@ func newTransactioner(accessMode neo4j.AccessMode) *transactioner {
 	return &transactioner{accessMode: accessMode}
 }
 
-func (t *transactioner) beginTransaction(s *sessionImpl, dbName string) (*transaction, error) {
+//errManagedTransaction is returned when the transaction of Transact is committed, rolled back or closed by work,
+//as Transact ends it
+func errManagedTransaction() error {
+	return newKindError(ErrTransactionState, "transaction is managed by Transact")
+}
+
+func (t *transactioner) beginTransaction(ctx context.Context, s *sessionImpl, dbName string) (*transaction, error) {
 	if t.transaction != nil {
-		return nil, errors.New("transaction already exists")
+		return nil, newKindError(ErrTransactionState, "transaction already exists")
 	}
 
 	var err error
-	if t.transaction, err = newTransaction(s.driver, t.endTransaction(s), t.accessMode, dbName); err != nil {
+	if t.transaction, err = newTransaction(ctx, s.driver, t.endTransaction(s), t.accessMode, dbName, s.store, s.eventer); err != nil {
 		return nil, err
 	}
 
@@ -59,7 +66,7 @@ func (t *transactioner) endTransaction(s *sessionImpl) func() error {
 	return func() error {
 		var err error
 		if t.transaction == nil {
-			return errors.New("no transaction exist")
+			return newKindError(ErrTransactionState, "no transaction exist")
 		}
 		if err = t.transaction.neo4jTransaction.Close(); err != nil {
 			return err
@@ -75,3 +82,89 @@ func (t *transactioner) endTransaction(s *sessionImpl) func() error {
 		return nil
 	}
 }
+
+//transact runs work in a transaction function of the driver, which commits when work returns nil, rolls back
+//when work returns an error or panics, and retries work on transient failures. The store is restored to its
+//state before the transaction on every retry and on rollback, so that it only reflects committed changes
+func (t *transactioner) transact(ctx context.Context, s *sessionImpl, dbName string, work func(Session) error) error {
+	var (
+		err         error
+		configurers []func(*neo4j.TransactionConfig)
+	)
+
+	if t.transaction != nil {
+		return newKindError(ErrTransactionState, "transaction already exists")
+	}
+	if err = ctx.Err(); err != nil {
+		return err
+	}
+	if configurers, err = transactionConfigurers(ctx); err != nil {
+		return err
+	}
+
+	sessionConfig := neo4j.SessionConfig{
+		AccessMode: t.accessMode,
+	}
+
+	if dbName != "" {
+		sessionConfig.DatabaseName = dbName
+	}
+
+	session := s.driver.NewSession(sessionConfig)
+	defer session.Close()
+	transactionMode := session.ReadTransaction
+	if t.accessMode == neo4j.AccessModeWrite {
+		transactionMode = session.WriteTransaction
+	}
+
+	var (
+		snapshot = s.store.snapshot()
+		attempts = 0
+		panicked interface{}
+	)
+	_, err = transactionMode(func(tx neo4j.Transaction) (result interface{}, err error) {
+		if attempts > 0 {
+			s.store.restore(snapshot)
+		}
+		attempts++
+		s.eventer.deferEvents()
+
+		//The snapshot keeps the versions and the timestamps set by work so that they're restored on retry and on rollback
+		t.transaction = &transaction{
+			neo4jTransaction: tx,
+			session:          session,
+			close:            errManagedTransaction,
+			dbName:           dbName,
+			ctx:              ctx,
+			snapshot:         snapshot,
+			managed:          true}
+		s.cypherExecuter.setTransaction(t.transaction)
+		defer func() {
+			t.transaction = nil
+			s.cypherExecuter.setTransaction(nil)
+			//The panic is raised again once the driver has rolled back the transaction
+			if panicked = recover(); panicked != nil {
+				err = errors.New("transaction function panicked")
+			}
+		}()
+
+		return nil, work(s)
+	}, configurers...)
+
+	if panicked != nil {
+		s.store.restore(snapshot)
+		s.eventer.discardEvents()
+		panic(panicked)
+	}
+	if err != nil {
+		s.store.restore(snapshot)
+		s.eventer.discardEvents()
+		if ctx.Err() != nil {
+			//The driver error is a consequence of the context being done
+			return ctx.Err()
+		}
+		return mapDriverError(err)
+	}
+	s.eventer.flushEvents()
+	return nil
+}
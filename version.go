@@ -0,0 +1,103 @@
+// MIT License
+//
+// Copyright (c) 2022 pmadhav
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package gogm
+
+import (
+	"errors"
+	"reflect"
+)
+
+func getVersionBackendName(structFields map[string]*reflect.StructField) (string, error) {
+	var versionBackendName string
+	for backendName, structField := range structFields {
+		if len(getNamespacedTag(structField.Tag).get(versionTag)) > 0 {
+			if versionBackendName != emptyString {
+				return emptyString, errors.New("Expected at most 1 field to be tagged 'version'")
+			}
+			switch structField.Type.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+				reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+				versionBackendName = backendName
+			default:
+				return emptyString, errors.New("invalid version type. Version type must be an integer")
+			}
+		}
+	}
+	return versionBackendName, nil
+}
+
+//getGraphVersion returns the version property name and the version of g. The name is empty when the domain
+//object of g isn't versioned
+func getGraphVersion(g graph, registry *registry, dbName string) (string, int64, error) {
+	if g.getValue() == nil || !g.getValue().IsValid() {
+		return emptyString, 0, nil
+	}
+	metadata, err := registry.get(g.getValue().Type(), dbName)
+	if err != nil {
+		return emptyString, 0, err
+	}
+	versionPropertyName, versionValue := metadata.getVersion(*g.getValue())
+	if versionPropertyName == emptyString {
+		return emptyString, 0, nil
+	}
+	if versionValue.CanInt() {
+		return versionPropertyName, versionValue.Int(), nil
+	}
+	return versionPropertyName, int64(versionValue.Uint()), nil
+}
+
+//setGraphVersion sets the version of the domain object of g, and of g so that the new version isn't seen as a change
+func setGraphVersion(g graph, registry *registry, dbName string, version int64) error {
+	metadata, err := registry.get(g.getValue().Type(), dbName)
+	if err != nil {
+		return err
+	}
+	versionPropertyName, versionValue := metadata.getVersion(*g.getValue())
+	if versionValue.CanInt() {
+		versionValue.SetInt(version)
+	} else {
+		versionValue.SetUint(uint64(version))
+	}
+	g.getProperties()[versionPropertyName] = versionValue.Interface()
+	return nil
+}
+
+//getLock returns the condition checking that the version of g in the database is the version of g, the set
+//incrementing it and their parameters. The condition begins with conjunction so that it can be appended to the
+//match of g. All are empty when g isn't versioned
+func getLock(g graph, conjunction string, registry *registry, dbName string) (string, string, map[string]interface{}, error) {
+	versionPropertyName, version, err := getGraphVersion(g, registry, dbName)
+	if err != nil || versionPropertyName == emptyString {
+		return emptyString, emptyString, nil, err
+	}
+	var (
+		sign          = g.getSignature()
+		versionCQLRef = sign + "Version"
+		property      = `coalesce(` + sign + `.` + versionPropertyName + `, 0)`
+	)
+	condition := conjunction + ` ` + property + ` = $` + versionCQLRef + `
+	`
+	set := `SET ` + sign + `.` + versionPropertyName + ` = ` + property + ` + 1
+	`
+	return condition, set, map[string]interface{}{versionCQLRef: version}, nil
+}